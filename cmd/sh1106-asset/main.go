@@ -0,0 +1,198 @@
+// Command sh1106-asset converts PNG, GIF, PBM, PGM and XBM images into Go
+// source holding page-packed *mono.Image values, so assets can be blitted to
+// the display buffer without decoding anything at runtime.
+//
+// It is meant to be run from go:generate:
+//
+//	//go:generate go run github.com/danielgatis/go-sh1106/cmd/sh1106-asset -o assets.go -dither atkinson logo.png battery.pbm
+//
+// Every input becomes an exported variable named after the file, e.g.
+// battery-full.png becomes BatteryFull.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"image"
+	_ "image/gif"
+	_ "image/png"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	_ "github.com/danielgatis/go-sh1106/pkg/netpbm"
+	_ "github.com/danielgatis/go-sh1106/pkg/xbm"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// asset is a converted image and the variable holding it
+type asset struct {
+	name   string
+	source string
+	img    *mono.Image
+}
+
+func main() {
+	out := flag.String("o", "", "output file (default stdout)")
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name of the generated file (default $GOPACKAGE or assets)")
+	name := flag.String("name", "", "variable name, only with a single input")
+	dither := flag.String("dither", mono.Threshold.String(), "threshold, floyd-steinberg, atkinson or bayer")
+	litIsBlack := flag.Bool("black", false, "light dark pixels, for artwork drawn black on white (PBM and XBM keep their own bits)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: sh1106-asset [flags] image...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *name != "" && flag.NArg() > 1 {
+		log.Fatal("-name needs a single input")
+	}
+	if *pkg == "" {
+		*pkg = "assets"
+	}
+
+	d, err := mono.ParseDither(*dither)
+	if err != nil {
+		log.Fatal(err)
+	}
+	polarity := mono.LitIsWhite
+	if *litIsBlack {
+		polarity = mono.LitIsBlack
+	}
+
+	var assets []asset
+	for _, path := range flag.Args() {
+		img, err := load(path, polarity, d)
+		if err != nil {
+			log.Fatal(err)
+		}
+		a := asset{name: *name, source: filepath.Base(path), img: img}
+		if a.name == "" {
+			a.name = identifier(a.source)
+		}
+		assets = append(assets, a)
+	}
+
+	var buf bytes.Buffer
+	if err := generate(&buf, *pkg, assets); err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+	} else {
+		err = os.WriteFile(*out, buf.Bytes(), 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load decodes an image file and converts it to a page-packed mono image at
+// the origin. PBM and XBM files already decode to mono images, whose bits
+// and polarity are kept as they are.
+func load(path string, polarity mono.Polarity, d mono.Dither) (*mono.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	img, ok := src.(*mono.Image)
+	if !ok {
+		img = mono.Convert(src, polarity, d)
+	}
+	if img.Rect.Min != (image.Point{}) {
+		moved := mono.New(image.Rectangle{Max: img.Rect.Size()})
+		moved.Polarity = img.Polarity
+		for y := 0; y < img.Rect.Dy(); y++ {
+			for x := 0; x < img.Rect.Dx(); x++ {
+				moved.SetPixel(x, y, img.GetPixel(img.Rect.Min.X+x, img.Rect.Min.Y+y))
+			}
+		}
+		img = moved
+	}
+
+	return img, nil
+}
+
+// identifier turns a file name into an exported Go identifier
+func identifier(file string) string {
+	base := strings.TrimSuffix(file, filepath.Ext(file))
+
+	var b strings.Builder
+	upper := true
+	for _, r := range base {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	s := b.String()
+	if s == "" || !unicode.IsLetter([]rune(s)[0]) {
+		s = "Asset" + s
+	}
+	return s
+}
+
+// generate writes the formatted Go source declaring the assets
+func generate(w io.Writer, pkg string, assets []asset) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by sh1106-asset; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n\t\"image\"\n\n\t\"github.com/danielgatis/go-sh1106/pkg/mono\"\n)\n")
+
+	seen := map[string]bool{}
+	for _, a := range assets {
+		if seen[a.name] {
+			return fmt.Errorf("duplicate asset name %s", a.name)
+		}
+		seen[a.name] = true
+
+		r := a.img.Rect
+		fmt.Fprintf(&buf, "\n// %s is %s, %dx%d pixels\n", a.name, a.source, r.Dx(), r.Dy())
+		fmt.Fprintf(&buf, "var %s = &mono.Image{\n", a.name)
+		fmt.Fprintf(&buf, "Pix: []byte{")
+		for i, b := range a.img.Pix {
+			if i%12 == 0 {
+				buf.WriteString("\n")
+			}
+			fmt.Fprintf(&buf, "0x%02x, ", b)
+		}
+		fmt.Fprintf(&buf, "\n},\n")
+		fmt.Fprintf(&buf, "Stride: %d,\n", a.img.Stride)
+		fmt.Fprintf(&buf, "Rect: image.Rect(0, 0, %d, %d),\n", r.Dx(), r.Dy())
+		if a.img.Polarity == mono.LitIsBlack {
+			fmt.Fprintf(&buf, "Polarity: mono.LitIsBlack,\n")
+		}
+		fmt.Fprintf(&buf, "}\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
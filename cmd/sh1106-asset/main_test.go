@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestIdentifier(t *testing.T) {
+	for in, want := range map[string]string{
+		"logo.png":         "Logo",
+		"battery-full.pbm": "BatteryFull",
+		"wifi_3.gif":       "Wifi3",
+		"8x8.png":          "Asset8x8",
+	} {
+		if got := identifier(in); got != want {
+			t.Errorf("Expected %q for %q, got %q", want, in, got)
+		}
+	}
+}
+
+func TestLoadAndGenerate(t *testing.T) {
+	// 10x10 PNG with a white diagonal
+	src := image.NewGray(image.Rect(0, 0, 10, 10))
+	for i := 0; i < 10; i++ {
+		src.SetGray(i, i, color.Gray{Y: 0xFF})
+	}
+	path := filepath.Join(t.TempDir(), "diag.png")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, src); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	img, err := load(path, mono.LitIsWhite, mono.Threshold)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			if img.GetPixel(x, y) != (x == y) {
+				t.Fatalf("Expected only the diagonal lit, pixel (%d, %d) is wrong", x, y)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := generate(&buf, "assets", []asset{{name: "Diag", source: "diag.png", img: img}}); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	out := buf.String()
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "assets.go", out, 0); err != nil {
+		t.Fatalf("Generated source does not parse: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"// Code generated by sh1106-asset; DO NOT EDIT.",
+		"package assets",
+		"var Diag = &mono.Image{",
+		"Stride: 10,",
+		"Rect:   image.Rect(0, 0, 10, 10),",
+		"0x01, 0x02, 0x04",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected generated source to contain %q\n%s", want, out)
+		}
+	}
+}
+
+func TestLoadBitmaps(t *testing.T) {
+	// 4x2 bitmaps with only the top left pixel black
+	for file, data := range map[string]string{
+		"dot.pbm": "P1\n4 2\n1 0 0 0\n0 0 0 0\n",
+		"dot.xbm": "#define dot_width 4\n#define dot_height 2\nstatic unsigned char dot_bits[] = {\n   0x01, 0x00 };\n",
+	} {
+		path := filepath.Join(t.TempDir(), file)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		// Bitmaps keep their bits whatever the polarity flag says
+		img, err := load(path, mono.LitIsWhite, mono.Threshold)
+		if err != nil {
+			t.Fatalf("load %s failed: %v", file, err)
+		}
+		if !bytes.Equal(img.Pix, []byte{0x01, 0x00, 0x00, 0x00}) {
+			t.Errorf("Expected only bit (0, 0) set for %s, got %#v", file, img.Pix)
+		}
+		if img.Polarity != mono.LitIsBlack {
+			t.Errorf("Expected polarity %v for %s, got %v", mono.LitIsBlack, file, img.Polarity)
+		}
+	}
+}
+
+func TestGenerateDuplicateNames(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 1, 1))
+	err := generate(&bytes.Buffer{}, "assets", []asset{{name: "A", img: img}, {name: "A", img: img}})
+	if err == nil {
+		t.Error("Expected an error for duplicate names")
+	}
+}
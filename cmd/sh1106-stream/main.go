@@ -0,0 +1,136 @@
+// Command sh1106-stream shows video read from stdin on the display, e.g.
+// piped from ffmpeg:
+//
+//	ffmpeg -re -i clip.mp4 -vf scale=128:64 -r 15 -f image2pipe -c:v pgm - | sh1106-stream -fps 15 -dither bayer
+//
+// Frames are PBM or PGM images, or raw page-packed frames with -format raw.
+// When the display cannot keep up, late frames are dropped so the video keeps
+// its pace.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/danielgatis/go-sh1106/pkg/display"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+	"github.com/danielgatis/go-sh1106/pkg/picture"
+	"github.com/danielgatis/go-sh1106/pkg/stream"
+
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/i2c/i2creg"
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/host/v3"
+)
+
+// config holds the parsed flags
+type config struct {
+	bus           string
+	width, height int
+	fps           float64
+	format        stream.Format
+	opts          picture.Options
+}
+
+func main() {
+	bus := flag.String("bus", "spi", "spi, or i2c to probe the bus for a display")
+	width := flag.Int("width", 128, "display width")
+	height := flag.Int("height", 64, "display height")
+	fps := flag.Float64("fps", 15, "frame rate of the stream, 0 to show frames as they arrive")
+	format := flag.String("format", stream.PNM.String(), "pnm or raw")
+	dither := flag.String("dither", mono.Threshold.String(), "threshold, floyd-steinberg, atkinson or bayer")
+	mode := flag.String("mode", picture.Fit.String(), "fit, fill or stretch frames of another size")
+	filter := flag.String("filter", picture.Box.String(), "nearest, bilinear or box")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: sh1106-stream [flags] < frames\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cfg, err := parse(*bus, *width, *height, *fps, *format, *dither, *mode, *filter)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := host.Init(); err != nil {
+		log.Fatal(err)
+	}
+	dev, err := open(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Turn the display off on Ctrl-C
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		dev.Halt()
+		os.Exit(130)
+	}()
+
+	r := stream.NewReader(os.Stdin, cfg.format, dev.Bounds(), &cfg.opts)
+	stats, err := stream.Play(dev, r, cfg.fps)
+	log.Printf("%d frames shown, %d dropped", stats.Shown, stats.Dropped)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parse validates the flags
+func parse(bus string, width, height int, fps float64, format, dither, mode, filter string) (*config, error) {
+	cfg := &config{bus: bus, width: width, height: height, fps: fps}
+	if bus != "spi" && bus != "i2c" {
+		return nil, fmt.Errorf("unknown bus %q", bus)
+	}
+	if width <= 0 || height <= 0 {
+		return nil, errors.New("display size must be positive")
+	}
+	if fps < 0 {
+		return nil, errors.New("frame rate must not be negative")
+	}
+
+	var err error
+	if cfg.format, err = stream.ParseFormat(format); err != nil {
+		return nil, err
+	}
+	if cfg.opts.Dither, err = mono.ParseDither(dither); err != nil {
+		return nil, err
+	}
+	if cfg.opts.Mode, err = picture.ParseMode(mode); err != nil {
+		return nil, err
+	}
+	if cfg.opts.Filter, err = picture.ParseFilter(filter); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// open initializes the display on the selected bus, wired like the examples
+func open(cfg *config) (*display.SH1106, error) {
+	opts := &display.Options{Width: cfg.width, Height: cfg.height}
+
+	if cfg.bus == "i2c" {
+		b, err := i2creg.Open("")
+		if err != nil {
+			return nil, err
+		}
+		return display.Probe(b, opts)
+	}
+
+	p, err := spireg.Open("")
+	if err != nil {
+		return nil, err
+	}
+	dc := gpioreg.ByName("GPIO24")
+	rst := gpioreg.ByName("GPIO25")
+	cs := gpioreg.ByName("GPIO8")
+	if dc == nil || rst == nil || cs == nil {
+		return nil, errors.New("GPIO24, GPIO25 and GPIO8 must be available")
+	}
+	return display.NewSH1106SPI(p, dc, rst, cs, opts)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+	"github.com/danielgatis/go-sh1106/pkg/picture"
+	"github.com/danielgatis/go-sh1106/pkg/stream"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := parse("i2c", 128, 32, 24, "raw", "bayer", "fill", "bilinear")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if cfg.format != stream.Raw || cfg.opts.Dither != mono.Bayer || cfg.opts.Mode != picture.Fill || cfg.opts.Filter != picture.Bilinear {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+
+	for _, args := range [][]string{
+		{"uart", "pnm", "threshold", "fit", "box"},
+		{"spi", "mp4", "threshold", "fit", "box"},
+		{"spi", "pnm", "noise", "fit", "box"},
+		{"spi", "pnm", "threshold", "zoom", "box"},
+		{"spi", "pnm", "threshold", "fit", "lanczos"},
+	} {
+		if _, err := parse(args[0], 128, 64, 15, args[1], args[2], args[3], args[4]); err == nil {
+			t.Errorf("Expected an error for %v", args)
+		}
+	}
+	if _, err := parse("spi", 0, 64, 15, "pnm", "threshold", "fit", "box"); err == nil {
+		t.Error("Expected an error for an empty display")
+	}
+	if _, err := parse("spi", 128, 64, -1, "pnm", "threshold", "fit", "box"); err == nil {
+		t.Error("Expected an error for a negative frame rate")
+	}
+}
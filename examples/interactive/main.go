@@ -138,11 +138,22 @@ func main() {
 		Width:     128,
 		Height:    64,
 		LineCount: 6,
+		Padding:   2,
 	})
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// Headers are centered on every screen
+	textRenderer.SetLineAlign(0, text.AlignCenter)
+
+	// Function to show a screen of lines with a default alignment
+	show := func(align text.Align, lines []string) error {
+		textRenderer.SetAlign(align)
+		textRenderer.SetTexts(lines)
+		return dev.Draw(textRenderer.Bounds(), textRenderer.Image(), image.Point{})
+	}
+
 	// Configure joystick GPIO pins
 	upPin := gpioreg.ByName("GPIO5")
 	downPin := gpioreg.ByName("GPIO6")
@@ -179,8 +190,7 @@ func main() {
 
 	// Function to update display
 	updateDisplay := func() {
-		textRenderer.SetTexts(menu.GetDisplayLines())
-		if err := dev.Draw(textRenderer.Bounds(), textRenderer.Image(), image.Point{}); err != nil {
+		if err := show(text.AlignLeft, menu.GetDisplayLines()); err != nil {
 			log.Printf("Error drawing: %v", err)
 		}
 	}
@@ -203,15 +213,14 @@ func main() {
 		fmt.Printf("Confirmed: %s\n", selected)
 
 		// Show confirmation on display
-		textRenderer.SetTexts([]string{
+		if err := show(text.AlignLeft, []string{
 			"SELECTED:",
 			"",
 			selected,
 			"",
 			"Press B2 to go",
 			"back to menu",
-		})
-		if err := dev.Draw(textRenderer.Bounds(), textRenderer.Image(), image.Point{}); err != nil {
+		}); err != nil {
 			log.Printf("Error drawing: %v", err)
 		}
 	})
@@ -223,15 +232,14 @@ func main() {
 
 	joy.OnClickButton3(func() {
 		fmt.Println("Exit requested")
-		textRenderer.SetTexts([]string{
+		if err := show(text.AlignCenter, []string{
 			"",
 			"",
-			"  GOODBYE!",
+			"GOODBYE!",
 			"",
 			"",
 			"",
-		})
-		if err := dev.Draw(textRenderer.Bounds(), textRenderer.Image(), image.Point{}); err != nil {
+		}); err != nil {
 			log.Printf("Error drawing: %v", err)
 		}
 		time.Sleep(2 * time.Second)
@@ -252,15 +260,14 @@ func main() {
 	})
 
 	// Show welcome screen
-	textRenderer.SetTexts([]string{
+	if err := show(text.AlignCenter, []string{
 		"",
-		"  INTERACTIVE",
-		"    MENU",
+		"INTERACTIVE",
+		"MENU",
 		"",
 		"Press any key",
 		"to start",
-	})
-	if err := dev.Draw(textRenderer.Bounds(), textRenderer.Image(), image.Point{}); err != nil {
+	}); err != nil {
 		log.Fatal(err)
 	}
 
@@ -309,15 +316,14 @@ func main() {
 	// Cleanup
 	fmt.Println("\nStopping...")
 	joy.Stop()
-	textRenderer.SetTexts([]string{
+	show(text.AlignCenter, []string{
 		"",
 		"",
-		"  SHUTDOWN",
+		"SHUTDOWN",
 		"",
 		"",
 		"",
 	})
-	dev.Draw(textRenderer.Bounds(), textRenderer.Image(), image.Point{})
 	time.Sleep(1 * time.Second)
 	fmt.Println("Goodbye!")
 }
@@ -0,0 +1,53 @@
+// Package raster holds small integer and bitmap helpers shared by the
+// drawing packages.
+package raster
+
+import (
+	"image"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// Abs returns the absolute value of v
+func Abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// FloorDiv divides rounding towards negative infinity
+func FloorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// Remap returns a copy of src where pixel (x, y) is read from f(x, y), both
+// relative to the top-left corner of src
+func Remap(src *mono.Image, f func(x, y int) (int, int)) *mono.Image {
+	dst := mono.New(src.Rect)
+	for y := 0; y < src.Rect.Dy(); y++ {
+		for x := 0; x < src.Rect.Dx(); x++ {
+			sx, sy := f(x, y)
+			dst.SetPixel(src.Rect.Min.X+x, src.Rect.Min.Y+y, src.GetPixel(src.Rect.Min.X+sx, src.Rect.Min.Y+sy))
+		}
+	}
+	return dst
+}
+
+// Count returns the number of lit pixels of img inside r
+func Count(img *mono.Image, r image.Rectangle) int {
+	r = r.Intersect(img.Rect)
+	n := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if img.GetPixel(x, y) {
+				n++
+			}
+		}
+	}
+	return n
+}
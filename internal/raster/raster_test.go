@@ -0,0 +1,46 @@
+package raster
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestFloorDiv(t *testing.T) {
+	for _, tc := range []struct{ a, b, want int }{
+		{7, 8, 0}, {8, 8, 1}, {-1, 8, -1}, {-8, 8, -1}, {-9, 8, -2}, {9, -8, -2},
+	} {
+		if got := FloorDiv(tc.a, tc.b); got != tc.want {
+			t.Errorf("Expected %d / %d = %d, got %d", tc.a, tc.b, tc.want, got)
+		}
+	}
+}
+
+func TestAbs(t *testing.T) {
+	if Abs(-3) != 3 || Abs(3) != 3 || Abs(0) != 0 {
+		t.Error("Expected absolute values")
+	}
+}
+
+func TestRemapAndCount(t *testing.T) {
+	src := mono.New(image.Rect(10, 20, 14, 23))
+	src.SetPixel(10, 20, true)
+	src.SetPixel(11, 22, true)
+
+	// Mirror left to right, relative to the top-left corner
+	dst := Remap(src, func(x, y int) (int, int) { return 3 - x, y })
+	if dst.Rect != src.Rect {
+		t.Errorf("Expected bounds %v, got %v", src.Rect, dst.Rect)
+	}
+	if !dst.GetPixel(13, 20) || !dst.GetPixel(12, 22) || dst.GetPixel(10, 20) {
+		t.Error("Expected the pixels mirrored")
+	}
+
+	if got := Count(dst, dst.Rect); got != 2 {
+		t.Errorf("Expected 2 lit pixels, got %d", got)
+	}
+	if got := Count(dst, image.Rect(0, 0, 13, 100)); got != 1 {
+		t.Errorf("Expected 1 lit pixel, got %d", got)
+	}
+}
@@ -0,0 +1,176 @@
+// Package barcode encodes Code 128 and EAN-13 barcodes and draws them onto
+// monochrome canvases with human-readable text underneath.
+package barcode
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/text"
+	"golang.org/x/image/font"
+)
+
+var (
+	// ErrInvalid is returned for data the symbology cannot encode
+	ErrInvalid = errors.New("barcode: invalid data")
+	// ErrTooSmall is returned when the barcode does not fit the target rectangle
+	ErrTooSmall = errors.New("barcode: rectangle too small for the barcode")
+)
+
+// symbology tells how the human-readable text is laid out
+type symbology int
+
+const (
+	code128 symbology = iota
+	ean13
+)
+
+// Barcode is an encoded 1D barcode made of equally wide modules
+type Barcode struct {
+	// Text is the human-readable text drawn under the bars
+	Text string
+
+	kind  symbology
+	bars  []bool
+	quiet int
+}
+
+// Len returns the width of the barcode in modules, without quiet zones
+func (b *Barcode) Len() int {
+	return len(b.bars)
+}
+
+// Bar reports whether module i is a bar
+func (b *Barcode) Bar(i int) bool {
+	return i >= 0 && i < len(b.bars) && b.bars[i]
+}
+
+// QuietZone returns the light margin in modules the symbology asks for on
+// each side
+func (b *Barcode) QuietZone() int {
+	return b.quiet
+}
+
+// appendWidths appends alternating bars and spaces of the given widths,
+// starting with a bar
+func (b *Barcode) appendWidths(widths string) {
+	for i, w := range widths {
+		for range int(w - '0') {
+			b.bars = append(b.bars, i%2 == 0)
+		}
+	}
+}
+
+// appendModules appends modules written as '1' for bars and '0' for spaces
+func (b *Barcode) appendModules(modules string) {
+	for _, m := range modules {
+		b.bars = append(b.bars, m == '1')
+	}
+}
+
+// Options controls how a barcode is drawn
+type Options struct {
+	// BarWidth is the module width in pixels; zero picks the largest that fits
+	BarWidth int
+	// Height is the height of the bars and text in pixels; zero fills the
+	// rectangle
+	Height int
+	// QuietZone is the light margin on each side in modules
+	QuietZone int
+	// HideText leaves out the human-readable text
+	HideText bool
+	// Inverted lights the bars. By default bars are unlit on a lit
+	// background, like a printed barcode, which most scanners expect.
+	Inverted bool
+	// Face is the font of the text, nil meaning the embedded font
+	Face font.Face
+}
+
+// Draw renders the barcode centered horizontally at the top of r and
+// returns the area it covers, quiet zones and the EAN-13 lead digit
+// included. Pixels of r outside that area are left untouched.
+func (b *Barcode) Draw(dst draw.Image, r image.Rectangle, opts *Options) (image.Rectangle, error) {
+	if opts == nil {
+		opts = &Options{QuietZone: b.quiet}
+	}
+	face := opts.Face
+	if face == nil {
+		face, _ = text.EmbeddedFace()
+	}
+
+	// The first EAN-13 digit sits left of the bars, so the left margin is
+	// widened to hold it when the quiet zone is too narrow
+	lead := 0
+	if !opts.HideText && b.kind == ean13 && len(b.Text) == 13 {
+		lead = text.Measure(face, b.Text[:1]) + 1
+	}
+
+	quiet := max(opts.QuietZone, 0)
+	modules := len(b.bars) + 2*quiet
+	bw := opts.BarWidth
+	if bw <= 0 {
+		bw = r.Dx() / modules
+		if lead > quiet*bw {
+			bw = (r.Dx() - lead) / (len(b.bars) + quiet)
+		}
+	}
+	height := opts.Height
+	if height <= 0 {
+		height = r.Dy()
+	}
+	left := max(quiet*bw, lead)
+	width := left + (len(b.bars)+quiet)*bw
+
+	textHeight := 0
+	if !opts.HideText {
+		textHeight = face.Metrics().Ascent.Round() + face.Metrics().Descent.Round() + 1
+	}
+	barHeight := height - textHeight
+	if bw <= 0 || width > r.Dx() || height > r.Dy() || barHeight <= 0 {
+		return image.Rectangle{}, ErrTooSmall
+	}
+
+	at := image.Pt(r.Min.X+(r.Dx()-width)/2, r.Min.Y)
+	area := image.Rectangle{Min: at, Max: at.Add(image.Pt(width, height))}
+
+	light, dark := gfx.On, gfx.Off
+	if opts.Inverted {
+		light, dark = dark, light
+	}
+	gfx.Rect(dst, area, gfx.Fill(light))
+
+	x0 := at.X + left
+	for i, bar := range b.bars {
+		if !bar {
+			continue
+		}
+		h := barHeight
+		if !opts.HideText && b.kind == ean13 && eanGuard(i) {
+			// Guard bars reach down between the digit groups
+			h += textHeight / 2
+		}
+		x := x0 + i*bw
+		gfx.Rect(dst, image.Rect(x, at.Y, x+bw, at.Y+h), gfx.Fill(dark))
+	}
+
+	if !opts.HideText {
+		baseline := area.Max.Y - face.Metrics().Descent.Round()
+		centered := func(s string, from, to int) {
+			w := text.Measure(face, s)
+			text.Draw(dst, face, image.Pt((from+to-w)/2, baseline), s, dark)
+		}
+
+		if b.kind == ean13 && len(b.Text) == 13 {
+			// First digit in the left margin, then one group per half
+			text.Draw(dst, face, image.Pt(x0-lead, baseline), b.Text[:1], dark)
+			centered(b.Text[1:7], x0+3*bw, x0+45*bw)
+			centered(b.Text[7:], x0+50*bw, x0+92*bw)
+		} else {
+			centered(b.Text, x0, x0+len(b.bars)*bw)
+		}
+	}
+
+	return area, nil
+}
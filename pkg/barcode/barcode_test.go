@@ -0,0 +1,134 @@
+package barcode
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestDraw(t *testing.T) {
+	b, err := Code128("SH1106")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	area, err := b.Draw(img, img.Rect, &Options{BarWidth: 1, Height: 30, QuietZone: 2})
+	if err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+
+	modules := b.Len() + 4
+	if want := image.Rect((128-modules)/2, 0, (128-modules)/2+modules, 30); area != want {
+		t.Fatalf("Expected area %v, got %v", want, area)
+	}
+	// Bars are unlit on a lit background
+	for i := 0; i < b.Len(); i++ {
+		if img.GetPixel(area.Min.X+2+i, 0) == b.Bar(i) {
+			t.Fatalf("Expected module %d to be drawn dark on light", i)
+		}
+	}
+	if !img.GetPixel(area.Min.X, 0) || img.GetPixel(0, 0) {
+		t.Error("Expected a lit quiet zone and untouched pixels outside it")
+	}
+
+	// The text lies under the bars, which stop above it
+	textTop := area.Max.Y - 7
+	dark := 0
+	for y := textTop; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if !img.GetPixel(x, y) {
+				dark++
+			}
+		}
+	}
+	if dark == 0 || dark > modules*7/2 {
+		t.Errorf("Expected text under the bars, got %d dark pixels", dark)
+	}
+}
+
+func TestDrawDefaults(t *testing.T) {
+	b, err := EAN13("400638133393")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	area, err := b.Draw(img, img.Rect, nil)
+	if err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+	// 95 modules and two standard quiet zones of 11 only fit one pixel wide
+	if area.Dx() != 117 || area.Dy() != 64 {
+		t.Errorf("Expected a 117x64 area, got %v", area)
+	}
+
+	// Guard bars reach lower than the digit bars
+	x0 := area.Min.X + 11
+	bottom := func(x int) int {
+		y := 0
+		for y < 64 && !img.GetPixel(x, y) {
+			y++
+		}
+		return y
+	}
+	if bottom(x0) <= bottom(x0+3) {
+		t.Errorf("Expected the start guard to be longer, got %d and %d", bottom(x0), bottom(x0+3))
+	}
+
+	// Inverted lights the bars
+	img.Fill(false)
+	if _, err := b.Draw(img, img.Rect, &Options{HideText: true, Inverted: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !img.GetPixel(x0, 63) || img.GetPixel(x0+1, 63) {
+		t.Error("Expected lit bars when inverted")
+	}
+
+	if _, err := b.Draw(img, image.Rect(0, 0, 90, 64), &Options{}); !errors.Is(err, ErrTooSmall) {
+		t.Errorf("Expected ErrTooSmall, got %v", err)
+	}
+	if _, err := b.Draw(img, image.Rect(0, 0, 128, 6), &Options{}); !errors.Is(err, ErrTooSmall) {
+		t.Errorf("Expected ErrTooSmall for a short rectangle, got %v", err)
+	}
+}
+
+func TestDrawEANWithoutQuietZone(t *testing.T) {
+	b, err := EAN13("400638133393")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Lit bars and text on an unlit canvas show anything drawn outside r
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	r := image.Rect(20, 0, 121, 40)
+	area, err := b.Draw(img, r, &Options{BarWidth: 1, QuietZone: 0, Inverted: true})
+	if err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+	if !area.In(r) {
+		t.Errorf("Expected the area inside %v, got %v", r, area)
+	}
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 128; x++ {
+			if img.GetPixel(x, y) && !image.Pt(x, y).In(r) {
+				t.Fatalf("Expected nothing drawn outside r, pixel (%d, %d) is lit", x, y)
+			}
+		}
+	}
+
+	// The lead digit sits left of the start guard
+	lead := 0
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X-95; x++ {
+			if img.GetPixel(x, y) {
+				lead++
+			}
+		}
+	}
+	if lead == 0 {
+		t.Error("Expected the lead digit left of the bars")
+	}
+}
@@ -0,0 +1,92 @@
+package barcode
+
+// code128Patterns holds the bar and space widths of symbols 0 to 105
+var code128Patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232",
+}
+
+// Code 128 control symbols
+const (
+	codeC  = 99
+	codeB  = 100
+	startB = 104
+	startC = 105
+	// stopPattern includes the final termination bar
+	stopPattern = "2331112"
+)
+
+// Code128 encodes printable ASCII text as a Code 128 barcode. Runs of
+// digits are packed two per symbol with code set C.
+func Code128(data string) (*Barcode, error) {
+	if data == "" {
+		return nil, ErrInvalid
+	}
+	for i := 0; i < len(data); i++ {
+		if data[i] < ' ' || data[i] > '~' {
+			return nil, ErrInvalid
+		}
+	}
+
+	var symbols []int
+	set := 0
+	for i := 0; i < len(data); {
+		// Code set C pays off for 4 or more digits, kept to an even count
+		run := digitRun(data[i:])
+		if run >= 4 || run == len(data) && run%2 == 0 {
+			switch set {
+			case 0:
+				symbols = append(symbols, startC)
+			case codeB:
+				symbols = append(symbols, codeC)
+			}
+			set = codeC
+			for end := i + run/2*2; i < end; i += 2 {
+				symbols = append(symbols, int(data[i]-'0')*10+int(data[i+1]-'0'))
+			}
+			continue
+		}
+
+		switch set {
+		case 0:
+			symbols = append(symbols, startB)
+		case codeC:
+			symbols = append(symbols, codeB)
+		}
+		set = codeB
+		symbols = append(symbols, int(data[i]-' '))
+		i++
+	}
+
+	// Weighted modulo 103 checksum, the start symbol weighing 1
+	sum := symbols[0]
+	for i, s := range symbols[1:] {
+		sum += (i + 1) * s
+	}
+	symbols = append(symbols, sum%103)
+
+	b := &Barcode{Text: data, kind: code128, quiet: 10}
+	for _, s := range symbols {
+		b.appendWidths(code128Patterns[s])
+	}
+	b.appendWidths(stopPattern)
+	return b, nil
+}
+
+// digitRun returns the number of leading digits of s
+func digitRun(s string) int {
+	n := 0
+	for n < len(s) && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
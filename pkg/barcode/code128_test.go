@@ -0,0 +1,104 @@
+package barcode
+
+import (
+	"errors"
+	"testing"
+)
+
+// widths returns the run lengths of the modules of b, starting with a bar
+func widths(b *Barcode) []int {
+	var runs []int
+	for i := 0; i < b.Len(); i++ {
+		if i == 0 || b.Bar(i) != b.Bar(i-1) {
+			runs = append(runs, 0)
+		}
+		runs[len(runs)-1]++
+	}
+	return runs
+}
+
+// symbols decodes the symbol values of a Code 128 barcode, stop excluded
+func symbols(t *testing.T, b *Barcode) []int {
+	t.Helper()
+	runs := widths(b)
+	if (len(runs)-7)%6 != 0 {
+		t.Fatalf("Expected 6 runs per symbol plus 7 for stop, got %d runs", len(runs))
+	}
+	var out []int
+	for i := 0; i+7 < len(runs); i += 6 {
+		var s []byte
+		for _, r := range runs[i : i+6] {
+			s = append(s, byte('0'+r))
+		}
+		found := -1
+		for v, p := range code128Patterns {
+			if p == string(s) {
+				found = v
+			}
+		}
+		if found < 0 {
+			t.Fatalf("Unknown symbol %s", s)
+		}
+		out = append(out, found)
+	}
+	return out
+}
+
+func TestCode128Patterns(t *testing.T) {
+	seen := map[string]bool{}
+	for v, p := range code128Patterns {
+		sum, bars := 0, 0
+		for i, c := range p {
+			sum += int(c - '0')
+			if i%2 == 0 {
+				bars += int(c - '0')
+			}
+		}
+		if sum != 11 || bars%2 != 0 || seen[p] {
+			t.Errorf("Symbol %d has an invalid pattern %s", v, p)
+		}
+		seen[p] = true
+	}
+}
+
+func TestCode128(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		want []int
+	}{
+		// Start B, P, J, J, 1, checksum
+		{"PJJ123C", []int{startB, 48, 42, 42, 17, 18, 19, 35, 55}},
+		{"1234", []int{startC, 12, 34, 82}},
+		{"12", []int{startC, 12, 14}},
+		{"A123456", []int{startB, 33, codeC, 12, 34, 56, 66}},
+		{"123456A", []int{startC, 12, 34, 56, codeB, 33, 94}},
+		{"12345", []int{startC, 12, 34, codeB, 21, 54}},
+	} {
+		b, err := Code128(tc.data)
+		if err != nil {
+			t.Fatalf("Code128(%q) failed: %v", tc.data, err)
+		}
+		got := symbols(t, b)
+		if len(got) != len(tc.want) {
+			t.Errorf("%q: Expected symbols %v, got %v", tc.data, tc.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%q: Expected symbols %v, got %v", tc.data, tc.want, got)
+				break
+			}
+		}
+		if b.Len() != len(got)*11+13 {
+			t.Errorf("%q: Expected %d modules, got %d", tc.data, len(got)*11+13, b.Len())
+		}
+	}
+}
+
+func TestCode128Invalid(t *testing.T) {
+	for _, data := range []string{"", "tab\there", "é"} {
+		if _, err := Code128(data); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%q: Expected ErrInvalid, got %v", data, err)
+		}
+	}
+}
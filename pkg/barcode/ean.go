@@ -0,0 +1,93 @@
+package barcode
+
+// EAN-13 digit encodings, L being the odd parity left hand set. The even
+// parity G set and the right hand R set are derived from it.
+var eanL = [10]string{
+	"0001101", "0011001", "0010011", "0111101", "0100011",
+	"0110001", "0101111", "0111011", "0110111", "0001011",
+}
+
+// eanParity tells, from the first digit, which left hand digits use the G set
+var eanParity = [10]string{
+	"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+	"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+}
+
+// eanR returns the right hand encoding of digit d, the complement of L
+func eanR(d int) string {
+	r := []byte(eanL[d])
+	for i, c := range r {
+		r[i] = '0' + '1' - c
+	}
+	return string(r)
+}
+
+// eanG returns the even parity encoding of digit d, R reversed
+func eanG(d int) string {
+	r := []byte(eanR(d))
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// eanGuard reports whether module i belongs to the start, center or end guard
+func eanGuard(i int) bool {
+	return i < 3 || i >= 45 && i < 50 || i >= 92
+}
+
+// EANCheckDigit returns the check digit of the first 12 digits of an EAN-13
+func EANCheckDigit(digits string) (byte, error) {
+	if len(digits) < 12 {
+		return 0, ErrInvalid
+	}
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := digits[i]
+		if d < '0' || d > '9' {
+			return 0, ErrInvalid
+		}
+		if i%2 == 0 {
+			sum += int(d - '0')
+		} else {
+			sum += 3 * int(d-'0')
+		}
+	}
+	return byte('0' + (10-sum%10)%10), nil
+}
+
+// EAN13 encodes 12 digits, appending the check digit, or 13 digits whose
+// check digit is verified
+func EAN13(digits string) (*Barcode, error) {
+	if len(digits) != 12 && len(digits) != 13 {
+		return nil, ErrInvalid
+	}
+	check, err := EANCheckDigit(digits)
+	if err != nil {
+		return nil, err
+	}
+	if len(digits) == 13 && digits[12] != check {
+		return nil, ErrInvalid
+	}
+	digits = digits[:12] + string(check)
+
+	b := &Barcode{Text: digits, kind: ean13, quiet: 11}
+	parity := eanParity[digits[0]-'0']
+
+	b.appendModules("101")
+	for i := 1; i <= 6; i++ {
+		d := int(digits[i] - '0')
+		if parity[i-1] == 'G' {
+			b.appendModules(eanG(d))
+		} else {
+			b.appendModules(eanL[d])
+		}
+	}
+	b.appendModules("01010")
+	for i := 7; i <= 12; i++ {
+		b.appendModules(eanR(int(digits[i] - '0')))
+	}
+	b.appendModules("101")
+
+	return b, nil
+}
@@ -0,0 +1,59 @@
+package barcode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEANCheckDigit(t *testing.T) {
+	for _, tc := range []struct {
+		digits string
+		want   byte
+	}{
+		{"400638133393", '1'},
+		{"590123412345", '7'},
+		{"000000000000", '0'},
+	} {
+		got, err := EANCheckDigit(tc.digits)
+		if err != nil || got != tc.want {
+			t.Errorf("%s: Expected check digit %c, got %c (%v)", tc.digits, tc.want, got, err)
+		}
+	}
+	if _, err := EANCheckDigit("40063813339x"); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected ErrInvalid, got %v", err)
+	}
+}
+
+func TestEAN13(t *testing.T) {
+	b, err := EAN13("400638133393")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Text != "4006381333931" {
+		t.Errorf("Expected text 4006381333931, got %s", b.Text)
+	}
+
+	// First digit 4 selects LGLLGG for 006381, then R codes for 333931
+	want := "101" +
+		"0001101" + "0100111" + "0101111" + "0111101" + "0001001" + "0110011" +
+		"01010" +
+		"1000010" + "1000010" + "1000010" + "1110100" + "1000010" + "1100110" +
+		"101"
+	if b.Len() != 95 {
+		t.Fatalf("Expected 95 modules, got %d", b.Len())
+	}
+	for i := range want {
+		if b.Bar(i) != (want[i] == '1') {
+			t.Fatalf("Module %d differs: expected %s", i, want)
+		}
+	}
+
+	if _, err := EAN13("4006381333931"); err != nil {
+		t.Errorf("Expected a valid check digit to be accepted, got %v", err)
+	}
+	for _, digits := range []string{"4006381333932", "12345", "40063813339a"} {
+		if _, err := EAN13(digits); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: Expected ErrInvalid, got %v", digits, err)
+		}
+	}
+}
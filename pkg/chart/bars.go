@@ -0,0 +1,159 @@
+package chart
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/text"
+	"golang.org/x/image/font"
+)
+
+// Bars is a bar chart growing from the zero line, vertical by default
+type Bars struct {
+	Values []float64
+	// Labels are written under vertical bars or left of horizontal ones
+	Labels []string
+	Scale  Scale
+
+	// Horizontal lays the bars out as rows growing to the right
+	Horizontal bool
+	// Gap is the space between bars in pixels
+	Gap int
+	// Style draws the bars; the zero value fills them. Use a fill pattern
+	// anchored to the shape to tell series apart.
+	Style gfx.Style
+
+	// Axis labels the ends of the scale
+	Axis bool
+	// Markers draws dashed lines at the lowest and highest values
+	Markers bool
+	// Face is the font of the labels, nil meaning the embedded font
+	Face font.Face
+}
+
+// Draw renders the chart into r, erasing it first
+func (b *Bars) Draw(dst draw.Image, r image.Rectangle) {
+	// Bar labels wider than their slot would spill onto the neighbours of r
+	dst = gfx.Clip(dst, r)
+	erase(dst, r)
+	if len(b.Values) == 0 || r.Empty() {
+		return
+	}
+
+	face := faceOr(b.Face)
+	lo, hi, step := b.Scale.resolve(b.Values, true)
+	style := b.Style
+	if style == (gfx.Style{}) {
+		style = gfx.Fill(gfx.On)
+	}
+
+	if b.Horizontal {
+		b.drawHorizontal(dst, r, face, lo, hi, step, style)
+	} else {
+		b.drawVertical(dst, r, face, lo, hi, step, style)
+	}
+}
+
+// drawVertical lays bars out left to right with the values growing upwards
+func (b *Bars) drawVertical(dst draw.Image, r image.Rectangle, face font.Face, lo, hi, step float64, style gfx.Style) {
+	plot := r
+	if len(b.Labels) > 0 {
+		plot.Max.Y -= ascent(face) + descent(face) + 1
+	}
+	if b.Axis {
+		plot = drawAxis(dst, plot, face, lo, hi, step)
+	}
+
+	y := func(v float64) int {
+		return plot.Max.Y - 1 - position(v, lo, hi, plot.Dy())
+	}
+	zero := y(0)
+
+	for i, v := range b.Values {
+		x0, x1 := slot(plot.Min.X, plot.Dx(), len(b.Values), b.Gap, i)
+		if x1 <= x0 {
+			continue
+		}
+		top, bottom := min(zero, y(v)), max(zero, y(v))
+		gfx.Rect(dst, image.Rect(x0, top, x1, bottom+1), style)
+
+		if i < len(b.Labels) {
+			w := text.Measure(face, b.Labels[i])
+			text.Draw(dst, face, image.Pt((x0+x1-w)/2, r.Max.Y-descent(face)), b.Labels[i], gfx.On)
+		}
+	}
+
+	if b.Markers {
+		lowest, highest := extremes(b.Values)
+		for _, v := range []float64{lowest, highest} {
+			gfx.DashedLine(dst, plot.Min.X, y(v), plot.Max.X-1, y(v), 1, 1, gfx.XOR)
+		}
+	}
+}
+
+// drawHorizontal lays bars out top to bottom with the values growing to the right
+func (b *Bars) drawHorizontal(dst draw.Image, r image.Rectangle, face font.Face, lo, hi, step float64, style gfx.Style) {
+	plot := r
+	labelWidth := 0
+	for _, l := range b.Labels {
+		labelWidth = max(labelWidth, text.Measure(face, l))
+	}
+	if labelWidth > 0 {
+		plot.Min.X += labelWidth + 1
+	}
+	if b.Axis {
+		// Scale ends go under the plot, the top one right aligned
+		plot.Max.Y -= ascent(face) + descent(face) + 1
+		base := r.Max.Y - descent(face)
+		top := label(hi, step)
+		text.Draw(dst, face, image.Pt(plot.Min.X, base), label(lo, step), gfx.On)
+		text.Draw(dst, face, image.Pt(plot.Max.X-text.Measure(face, top), base), top, gfx.On)
+		gfx.Line(dst, plot.Min.X, plot.Max.Y, plot.Max.X-1, plot.Max.Y, gfx.On)
+	}
+
+	x := func(v float64) int {
+		return plot.Min.X + position(v, lo, hi, plot.Dx())
+	}
+	zero := x(0)
+
+	for i, v := range b.Values {
+		y0, y1 := slot(plot.Min.Y, plot.Dy(), len(b.Values), b.Gap, i)
+		if y1 <= y0 {
+			continue
+		}
+		left, right := min(zero, x(v)), max(zero, x(v))
+		gfx.Rect(dst, image.Rect(left, y0, right+1, y1), style)
+
+		if i < len(b.Labels) {
+			// Vertically centered on the bar
+			base := (y0 + y1 + ascent(face)) / 2
+			text.Draw(dst, face, image.Pt(r.Min.X+labelWidth-text.Measure(face, b.Labels[i]), base), b.Labels[i], gfx.On)
+		}
+	}
+
+	if b.Markers {
+		lowest, highest := extremes(b.Values)
+		for _, v := range []float64{lowest, highest} {
+			gfx.DashedLine(dst, x(v), plot.Min.Y, x(v), plot.Max.Y-1, 1, 1, gfx.XOR)
+		}
+	}
+}
+
+// slot returns the extent of bar i of n sharing length pixels from start
+func slot(start, length, n, gap, i int) (int, int) {
+	gap = max(gap, 0)
+	avail := length - gap*(n-1)
+	from := start + i*avail/n + i*gap
+	to := start + (i+1)*avail/n + i*gap
+	return from, to
+}
+
+// extremes returns the lowest and highest of values
+func extremes(values []float64) (float64, float64) {
+	lo, hi := values[0], values[0]
+	for _, v := range values[1:] {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	return lo, hi
+}
@@ -0,0 +1,91 @@
+package chart
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestBarsVertical(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 40, 21))
+	b := &Bars{Values: []float64{10, 5, 0, 2.5}, Scale: Scale{Min: 0, Max: 10}, Gap: 0}
+	b.Draw(img, img.Rect)
+
+	// 4 bars of 10 columns; heights follow the values
+	heights := []int{21, 11, 1, 6}
+	for i, h := range heights {
+		if got := raster.Count(img, image.Rect(i*10, 0, i*10+1, 21)); got != h {
+			t.Errorf("Expected bar %d to be %d pixels high, got %d", i, h, got)
+		}
+	}
+}
+
+func TestBarsNegative(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 20, 21))
+	(&Bars{Values: []float64{10, -10}, Gap: 2}).Draw(img, img.Rect)
+
+	// The zero line is in the middle, the negative bar grows downwards
+	if !img.GetPixel(0, 0) || img.GetPixel(0, 20) {
+		t.Error("Expected the positive bar above the zero line")
+	}
+	if !img.GetPixel(19, 20) || img.GetPixel(19, 0) {
+		t.Error("Expected the negative bar below the zero line")
+	}
+	if raster.Count(img, image.Rect(9, 0, 11, 21)) != 0 {
+		t.Error("Expected a gap between the bars")
+	}
+}
+
+func TestBarsHorizontal(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 21, 10))
+	(&Bars{Values: []float64{10, 5}, Scale: Scale{Min: 0, Max: 10}, Horizontal: true}).Draw(img, img.Rect)
+
+	if got := raster.Count(img, image.Rect(0, 0, 21, 1)); got != 21 {
+		t.Errorf("Expected the first bar to span 21 pixels, got %d", got)
+	}
+	if got := raster.Count(img, image.Rect(0, 9, 21, 10)); got != 11 {
+		t.Errorf("Expected the second bar to span 11 pixels, got %d", got)
+	}
+}
+
+func TestBarsLabelsAndStyle(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 40, 32))
+	b := &Bars{
+		Values: []float64{4, 8},
+		Labels: []string{"A", "B"},
+		Style:  gfx.Stroke(gfx.On),
+		Gap:    4,
+	}
+	b.Draw(img, img.Rect)
+
+	// Labels sit in the bottom text row, bars end above it
+	if raster.Count(img, image.Rect(0, 26, 40, 32)) == 0 {
+		t.Error("Expected labels under the bars")
+	}
+	// Outlined bars are hollow
+	if img.GetPixel(30, 20) {
+		t.Error("Expected outlined bars to be hollow")
+	}
+}
+
+func TestBarsPattern(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 40, 32))
+	style := gfx.FillPattern(gfx.On, gfx.Checker)
+	style.Anchor = gfx.AnchorShape
+	b := &Bars{Values: []float64{8, 8}, Gap: 4, Style: style}
+	b.Draw(img, img.Rect)
+
+	// Each bar starts its checker on a lit pixel, half of it lit
+	lit := raster.Count(img, img.Rect)
+	solid := mono.New(img.Rect)
+	(&Bars{Values: []float64{8, 8}, Gap: 4}).Draw(solid, solid.Rect)
+	if all := raster.Count(solid, solid.Rect); lit < all/2-2 || lit > all/2+2 {
+		t.Errorf("Expected about half of %d pixels, got %d", all, lit)
+	}
+	if !img.GetPixel(0, 0) {
+		t.Error("Expected the pattern to start at the corner of the bar")
+	}
+}
@@ -0,0 +1,163 @@
+// Package chart provides telemetry widgets that render into a rectangle of a
+// monochrome canvas: sparklines, bar charts, radial gauges and progress bars.
+package chart
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"strconv"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/text"
+	"golang.org/x/image/font"
+)
+
+// Scale is the range of values mapped onto a chart axis. The zero Scale
+// adapts to the data, rounded out to a readable step.
+type Scale struct {
+	Min float64
+	Max float64
+}
+
+// Auto reports whether the scale adapts to the data
+func (s Scale) Auto() bool {
+	return s.Min == s.Max
+}
+
+// resolve returns the scale bounds and the step used to format labels. An
+// automatic scale covers values and, if zero is set, the zero line.
+func (s Scale) resolve(values []float64, zero bool) (lo, hi, step float64) {
+	if !s.Auto() {
+		lo, hi = min(s.Min, s.Max), max(s.Min, s.Max)
+		return lo, hi, niceStep((hi - lo) / 4)
+	}
+
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	if zero {
+		lo, hi = min(lo, 0), max(hi, 0)
+	}
+	switch {
+	case lo > hi:
+		lo, hi = 0, 1
+	case lo == hi:
+		lo, hi = lo-1, hi+1
+	}
+
+	step = niceStep((hi - lo) / 4)
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step, step
+}
+
+// niceStep returns the smallest of 1, 2 or 5 times a power of ten not below x
+func niceStep(x float64) float64 {
+	if x <= 0 || math.IsInf(x, 0) || math.IsNaN(x) {
+		return 1
+	}
+	p := math.Pow(10, math.Floor(math.Log10(x)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*p >= x*(1-1e-9) {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+// label formats v with as many decimals as step needs
+func label(v, step float64) string {
+	decimals := 0
+	if step > 0 && step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step) - 1e-9))
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+// position maps v within lo and hi onto n pixels, clamping outside values
+func position(v, lo, hi float64, n int) int {
+	if hi <= lo || n <= 1 || math.IsNaN(v) {
+		return 0
+	}
+	f := (v - lo) / (hi - lo)
+	return int(math.Round(max(0, min(1, f)) * float64(n-1)))
+}
+
+// faceOr returns face, or the embedded font when face is nil
+func faceOr(face font.Face) font.Face {
+	if face != nil {
+		return face
+	}
+	f, _ := text.EmbeddedFace()
+	return f
+}
+
+// ascent returns the height of the face above the baseline in pixels
+func ascent(face font.Face) int {
+	return face.Metrics().Ascent.Round()
+}
+
+// descent returns the depth of the face below the baseline in pixels
+func descent(face font.Face) int {
+	return face.Metrics().Descent.Round()
+}
+
+// erase darkens r
+func erase(dst draw.Image, r image.Rectangle) {
+	gfx.Rect(dst, r, gfx.Fill(gfx.Off))
+}
+
+// Ring is a fixed size buffer of samples that drops the oldest sample when
+// full, e.g. to feed a sparkline from a sensor
+type Ring struct {
+	buf   []float64
+	start int
+	n     int
+}
+
+// NewRing returns an empty ring holding up to size samples
+func NewRing(size int) *Ring {
+	return &Ring{buf: make([]float64, max(size, 1))}
+}
+
+// Push appends a sample, dropping the oldest one when the ring is full
+func (r *Ring) Push(v float64) {
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = v
+		r.n++
+		return
+	}
+	r.buf[r.start] = v
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+// Len returns the number of samples
+func (r *Ring) Len() int {
+	return r.n
+}
+
+// Cap returns the maximum number of samples
+func (r *Ring) Cap() int {
+	return len(r.buf)
+}
+
+// At returns sample i, 0 being the oldest
+func (r *Ring) At(i int) float64 {
+	return r.buf[(r.start+i)%len(r.buf)]
+}
+
+// Values returns the samples, oldest first
+func (r *Ring) Values() []float64 {
+	v := make([]float64, r.n)
+	for i := range v {
+		v[i] = r.At(i)
+	}
+	return v
+}
+
+// Reset drops all samples
+func (r *Ring) Reset() {
+	r.start, r.n = 0, 0
+}
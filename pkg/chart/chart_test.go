@@ -0,0 +1,66 @@
+package chart
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScaleResolve(t *testing.T) {
+	tests := []struct {
+		scale      Scale
+		values     []float64
+		zero       bool
+		lo, hi, st float64
+	}{
+		{Scale{}, []float64{3, 17}, false, 0, 20, 5},
+		{Scale{}, []float64{0.12, 0.47}, false, 0.1, 0.5, 0.1},
+		{Scale{}, []float64{5, 9}, true, 0, 10, 5},
+		{Scale{}, nil, false, 0, 1, 0.5},
+		{Scale{}, []float64{4, 4}, false, 3, 5, 0.5},
+		{Scale{Min: 100, Max: 0}, []float64{500}, false, 0, 100, 50},
+	}
+	for _, tc := range tests {
+		lo, hi, st := tc.scale.resolve(tc.values, tc.zero)
+		if lo != tc.lo || hi != tc.hi || st != tc.st {
+			t.Errorf("%v of %v: Expected %v..%v step %v, got %v..%v step %v", tc.scale, tc.values, tc.lo, tc.hi, tc.st, lo, hi, st)
+		}
+	}
+}
+
+func TestLabel(t *testing.T) {
+	for _, tc := range []struct {
+		v, step float64
+		want    string
+	}{
+		{20, 5, "20"},
+		{0.5, 0.1, "0.5"},
+		{0.25, 0.05, "0.25"},
+		{-3, 1, "-3"},
+	} {
+		if got := label(tc.v, tc.step); got != tc.want {
+			t.Errorf("Expected %q, got %q", tc.want, got)
+		}
+	}
+}
+
+func TestRing(t *testing.T) {
+	r := NewRing(3)
+	if r.Len() != 0 || r.Cap() != 3 {
+		t.Fatalf("Expected an empty ring of 3, got %d of %d", r.Len(), r.Cap())
+	}
+
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		r.Push(v)
+	}
+	if got := r.Values(); !slices.Equal(got, []float64{3, 4, 5}) {
+		t.Errorf("Expected the last 3 samples, got %v", got)
+	}
+	if r.At(0) != 3 {
+		t.Errorf("Expected the oldest sample first, got %v", r.At(0))
+	}
+
+	r.Reset()
+	if r.Len() != 0 {
+		t.Errorf("Expected Reset to empty the ring, got %d samples", r.Len())
+	}
+}
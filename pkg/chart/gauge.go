@@ -0,0 +1,135 @@
+package chart
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/text"
+	"golang.org/x/image/font"
+)
+
+// The gauge dial opens at the bottom, sweeping 240 degrees clockwise from
+// lower left to lower right
+const (
+	gaugeStart = 210.0
+	gaugeSweep = 240.0
+)
+
+// Gauge is a radial dial with a needle
+type Gauge struct {
+	Value float64
+	// Scale is the range of the dial; the zero Scale means 0 to 1
+	Scale Scale
+	// Ticks divides the dial into that many intervals with tick marks
+	Ticks int
+	// Label is written under the hub
+	Label string
+
+	// Markers places short inner ticks at Low and High, e.g. to show the
+	// extremes recorded so far
+	Markers bool
+	Low     float64
+	High    float64
+
+	// Face is the font of the label, nil meaning the embedded font
+	Face font.Face
+}
+
+// Draw renders the gauge into r, erasing it first
+func (g *Gauge) Draw(dst draw.Image, r image.Rectangle) {
+	// A label wider than the dial would spill onto the neighbours of r
+	dst = gfx.Clip(dst, r)
+	erase(dst, r)
+	if r.Empty() {
+		return
+	}
+
+	lo, hi := 0.0, 1.0
+	if !g.Scale.Auto() {
+		lo, hi = min(g.Scale.Min, g.Scale.Max), max(g.Scale.Min, g.Scale.Max)
+	}
+
+	// The dial is 1.5 radii tall since it opens 30 degrees below the hub
+	radius := min((r.Dx()-1)/2, (r.Dy()-1)*2/3)
+	if radius < 3 {
+		return
+	}
+	c := image.Pt(r.Min.X+r.Dx()/2, r.Min.Y+radius)
+
+	gfx.Arc(dst, c, radius, gaugeStart-gaugeSweep, gaugeStart, gfx.On)
+
+	angle := func(v float64) float64 {
+		f := 0.0
+		if hi > lo {
+			f = max(0, min(1, (v-lo)/(hi-lo)))
+		}
+		return (gaugeStart - f*gaugeSweep) * math.Pi / 180
+	}
+	spoke := func(a float64, from, to int, ink gfx.Ink) {
+		dx, dy := math.Cos(a), -math.Sin(a)
+		gfx.Line(dst,
+			c.X+int(math.Round(dx*float64(from))), c.Y+int(math.Round(dy*float64(from))),
+			c.X+int(math.Round(dx*float64(to))), c.Y+int(math.Round(dy*float64(to))),
+			ink)
+	}
+
+	if g.Ticks > 0 {
+		for i := 0; i <= g.Ticks; i++ {
+			spoke(angle(lo+(hi-lo)*float64(i)/float64(g.Ticks)), radius-2, radius, gfx.On)
+		}
+	}
+	if g.Markers {
+		for _, v := range []float64{g.Low, g.High} {
+			spoke(angle(v), radius-5, radius-4, gfx.On)
+		}
+	}
+
+	spoke(angle(g.Value), 0, radius-3, gfx.On)
+	gfx.Circle(dst, c, 1, gfx.Fill(gfx.On))
+
+	if g.Label != "" {
+		face := faceOr(g.Face)
+		w := text.Measure(face, g.Label)
+		text.Draw(dst, face, image.Pt(c.X-w/2, r.Max.Y-descent(face)), g.Label, gfx.On)
+	}
+}
+
+// Progress is a horizontal progress bar with an optional centered label
+type Progress struct {
+	// Value is the completed fraction, clamped to 0..1
+	Value float64
+	// Label is drawn inverted over the bar so it reads on both sides of the fill
+	Label string
+	// Face is the font of the label, nil meaning the embedded font
+	Face font.Face
+}
+
+// Draw renders the bar into r, erasing it first
+func (p *Progress) Draw(dst draw.Image, r image.Rectangle) {
+	// A label wider or taller than the bar would spill onto the neighbours of r
+	dst = gfx.Clip(dst, r)
+	erase(dst, r)
+	if r.Empty() {
+		return
+	}
+
+	gfx.Rect(dst, r, gfx.Stroke(gfx.On))
+	inner := r.Inset(2)
+	if !inner.Empty() {
+		v := max(0, min(1, p.Value))
+		if math.IsNaN(v) {
+			v = 0
+		}
+		inner.Max.X = inner.Min.X + int(math.Round(v*float64(inner.Dx())))
+		gfx.Rect(dst, inner, gfx.Fill(gfx.On))
+	}
+
+	if p.Label != "" {
+		face := faceOr(p.Face)
+		w := text.Measure(face, p.Label)
+		base := (r.Min.Y + r.Max.Y + ascent(face)) / 2
+		text.Draw(dst, face, image.Pt(r.Min.X+(r.Dx()-w)/2, base), p.Label, gfx.XOR)
+	}
+}
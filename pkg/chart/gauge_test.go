@@ -0,0 +1,61 @@
+package chart
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestGaugeNeedle(t *testing.T) {
+	r := image.Rect(0, 0, 41, 31)
+	for _, tc := range []struct {
+		value float64
+		probe image.Point
+	}{
+		{0.5, image.Pt(20, 5)}, // Straight up
+		{0, image.Pt(10, 25)},  // Lower left
+		{1, image.Pt(30, 25)},  // Lower right
+		{-3, image.Pt(10, 25)}, // Clamped
+	} {
+		img := mono.New(r)
+		(&Gauge{Value: tc.value}).Draw(img, r)
+		near := raster.Count(img, image.Rectangle{Min: tc.probe.Sub(image.Pt(2, 2)), Max: tc.probe.Add(image.Pt(3, 3))})
+		if near == 0 {
+			t.Errorf("Expected the needle for %v near %v", tc.value, tc.probe)
+		}
+	}
+}
+
+func TestGaugeTicksAndLabel(t *testing.T) {
+	r := image.Rect(0, 0, 41, 31)
+	plain := mono.New(r)
+	(&Gauge{Value: 50, Scale: Scale{Max: 100}}).Draw(plain, r)
+
+	full := mono.New(r)
+	(&Gauge{Value: 50, Scale: Scale{Max: 100}, Ticks: 4, Label: "50%", Markers: true, Low: 10, High: 90}).Draw(full, r)
+	if raster.Count(full, r) <= raster.Count(plain, r) {
+		t.Error("Expected ticks, markers and label to add pixels")
+	}
+	if raster.Count(full, image.Rect(0, 25, 41, 31)) <= raster.Count(plain, image.Rect(0, 25, 41, 31)) {
+		t.Error("Expected the label at the bottom")
+	}
+}
+
+func TestProgress(t *testing.T) {
+	r := image.Rect(0, 0, 44, 10)
+	img := mono.New(r)
+	(&Progress{Value: 0.5}).Draw(img, r)
+
+	// 2 pixel inset, half of the 40 inner columns filled
+	if got := raster.Count(img, image.Rect(2, 5, 42, 6)); got != 20 {
+		t.Errorf("Expected 20 filled columns, got %d", got)
+	}
+
+	// The label is inverted over the fill
+	(&Progress{Value: 1, Label: "OK"}).Draw(img, r)
+	if raster.Count(img, r.Inset(2)) == r.Inset(2).Dx()*r.Inset(2).Dy() {
+		t.Error("Expected the label to be cut out of the fill")
+	}
+}
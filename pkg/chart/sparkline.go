@@ -0,0 +1,96 @@
+package chart
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/text"
+	"golang.org/x/image/font"
+)
+
+// Sparkline plots the samples of a ring as a line, one pixel column per
+// sample with the newest on the right
+type Sparkline struct {
+	Data  *Ring
+	Scale Scale
+
+	// Axis labels the top and bottom of the scale left of the plot
+	Axis bool
+	// Markers boxes the lowest and highest samples shown
+	Markers bool
+	// Face is the font of the axis labels, nil meaning the embedded font
+	Face font.Face
+}
+
+// Draw renders the sparkline into r, erasing it first
+func (s *Sparkline) Draw(dst draw.Image, r image.Rectangle) {
+	// Axis labels wider or taller than r would spill onto its neighbours
+	dst = gfx.Clip(dst, r)
+	erase(dst, r)
+	if s.Data == nil || r.Empty() {
+		return
+	}
+
+	values := s.Data.Values()
+	lo, hi, step := s.Scale.resolve(values, false)
+
+	plot := r
+	if s.Axis {
+		plot = drawAxis(dst, r, faceOr(s.Face), lo, hi, step)
+	}
+	if plot.Empty() {
+		// The labels took all the room
+		return
+	}
+
+	n := max(0, min(len(values), plot.Dx()))
+	values = values[len(values)-n:]
+	point := func(i int) image.Point {
+		return image.Pt(plot.Max.X-n+i, plot.Max.Y-1-position(values[i], lo, hi, plot.Dy()))
+	}
+
+	for i := range values {
+		p := point(i)
+		if i == 0 {
+			gfx.Pixel(dst, p.X, p.Y, gfx.On)
+			continue
+		}
+		q := point(i - 1)
+		gfx.Line(dst, q.X, q.Y, p.X, p.Y, gfx.On)
+	}
+
+	if s.Markers && n > 0 {
+		lowest, highest := 0, 0
+		for i, v := range values {
+			if v < values[lowest] {
+				lowest = i
+			}
+			if v > values[highest] {
+				highest = i
+			}
+		}
+		for _, i := range []int{lowest, highest} {
+			p := point(i)
+			box := image.Rect(p.X-1, p.Y-1, p.X+2, p.Y+2).Intersect(plot)
+			gfx.Rect(dst, box, gfx.Stroke(gfx.On))
+		}
+	}
+}
+
+// drawAxis labels the top and bottom of the scale along the left edge of r
+// with a vertical rule, and returns the area left for the plot
+func drawAxis(dst draw.Image, r image.Rectangle, face font.Face, lo, hi, step float64) image.Rectangle {
+	top, bottom := label(hi, step), label(lo, step)
+	w := max(text.Measure(face, top), text.Measure(face, bottom))
+
+	text.Draw(dst, face, image.Pt(r.Min.X+w-text.Measure(face, top), r.Min.Y+ascent(face)), top, gfx.On)
+	text.Draw(dst, face, image.Pt(r.Min.X+w-text.Measure(face, bottom), r.Max.Y-descent(face)), bottom, gfx.On)
+
+	x := r.Min.X + w + 1
+	gfx.Line(dst, x, r.Min.Y, x, r.Max.Y-1, gfx.On)
+
+	plot := r
+	plot.Min.X = x + 2
+	return plot
+}
@@ -0,0 +1,114 @@
+package chart
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestSparkline(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 64, 32))
+	img.Fill(true)
+	r := image.Rect(10, 5, 30, 25)
+
+	data := NewRing(40)
+	for i := range 40 {
+		data.Push(float64(i % 10))
+	}
+	s := &Sparkline{Data: data, Scale: Scale{Min: 0, Max: 9}}
+	s.Draw(img, r)
+
+	// Outside r is untouched
+	if raster.Count(img, img.Rect)-raster.Count(img, r) != 64*32-r.Dx()*r.Dy() {
+		t.Error("Expected pixels outside the chart to be untouched")
+	}
+
+	// The newest sample (9) is plotted at the top right, one column per sample
+	if !img.GetPixel(r.Max.X-1, r.Min.Y) {
+		t.Error("Expected the newest sample at the top right corner")
+	}
+	for x := r.Min.X; x < r.Max.X; x++ {
+		if raster.Count(img, image.Rect(x, r.Min.Y, x+1, r.Max.Y)) == 0 {
+			t.Errorf("Expected column %d to be plotted", x)
+		}
+	}
+}
+
+func TestSparklineShortData(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 16))
+	data := NewRing(8)
+	data.Push(1)
+	data.Push(2)
+
+	(&Sparkline{Data: data}).Draw(img, img.Rect)
+
+	// Two samples on the right edge and nothing to the left
+	if raster.Count(img, image.Rect(0, 0, 30, 16)) != 0 {
+		t.Error("Expected the samples to be anchored on the right")
+	}
+	if raster.Count(img, image.Rect(30, 0, 32, 16)) == 0 {
+		t.Error("Expected the samples to be plotted")
+	}
+}
+
+func TestSparklineAxisAndMarkers(t *testing.T) {
+	data := NewRing(16)
+	for _, v := range []float64{5, 5, 5, 0, 5, 5, 10, 5} {
+		data.Push(v)
+	}
+
+	plain := mono.New(image.Rect(0, 0, 64, 32))
+	(&Sparkline{Data: data}).Draw(plain, plain.Rect)
+
+	axis := mono.New(image.Rect(0, 0, 64, 32))
+	(&Sparkline{Data: data, Axis: true}).Draw(axis, axis.Rect)
+	if raster.Count(axis, image.Rect(0, 0, 8, 32)) == 0 {
+		t.Error("Expected axis labels on the left")
+	}
+
+	marked := mono.New(image.Rect(0, 0, 64, 32))
+	(&Sparkline{Data: data, Markers: true}).Draw(marked, marked.Rect)
+	if raster.Count(marked, marked.Rect) <= raster.Count(plain, plain.Rect) {
+		t.Error("Expected markers to add pixels")
+	}
+
+	// The highest sample is boxed: the pixels beside it are lit
+	x := 64 - 2
+	if !marked.GetPixel(x-1, 0) || !marked.GetPixel(x+1, 1) {
+		t.Error("Expected a box around the highest sample")
+	}
+}
+
+func TestSparklineNarrowAxis(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+	data := NewRing(30)
+	for i := range 23 {
+		data.Push(float64(i * 100))
+	}
+
+	// The axis labels are wider than the rectangle
+	r := image.Rect(0, 0, 6, 20)
+	(&Sparkline{Data: data, Axis: true, Markers: true}).Draw(img, r)
+	if raster.Count(img, img.Rect) != raster.Count(img, r) {
+		t.Error("Expected the labels to be clipped to the chart")
+	}
+}
+
+func TestSparklineClipsToRect(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 64, 32))
+	data := NewRing(8)
+	for i := range 8 {
+		data.Push(float64(i))
+	}
+
+	r := image.Rect(20, 8, 40, 24)
+	(&Sparkline{Data: data, Axis: true, Markers: true}).Draw(img, r)
+	if raster.Count(img, r) == 0 {
+		t.Error("Expected the sparkline to be drawn")
+	}
+	if raster.Count(img, img.Rect) != raster.Count(img, r) {
+		t.Error("Expected nothing drawn outside the chart")
+	}
+}
@@ -0,0 +1,145 @@
+package clock
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"time"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+)
+
+// hand is a clock hand at one of its positions around the dial
+type hand struct {
+	// pos counts steps clockwise from 12 out of steps per turn
+	pos   int
+	steps int
+	// length is the distance from the hub to the tip
+	length float64
+	width  float64
+}
+
+// path returns the outline of the hand around center c
+func (h hand) path(c image.Point) *gfx.Path {
+	a := 2 * math.Pi * float64(h.pos) / float64(h.steps)
+	p := &gfx.Path{}
+	p.MoveTo(float64(c.X), float64(c.Y))
+	p.LineTo(float64(c.X)+h.length*math.Sin(a), float64(c.Y)-h.length*math.Cos(a))
+	return p
+}
+
+// draw inks the hand and returns the area it covers
+func (h hand) draw(dst draw.Image, c image.Point, ink gfx.Ink) image.Rectangle {
+	p := h.path(c)
+	gfx.DrawPath(dst, p, gfx.Stroke(ink), h.width, gfx.NonZero)
+	return p.Bounds().Inset(-int(math.Ceil(h.width / 2)))
+}
+
+// Analog is a round clock face with tick marks and hour, minute and
+// optionally second hands
+type Analog struct {
+	// Seconds shows the second hand
+	Seconds bool
+	// Minutes adds a dot for every minute between the hour ticks
+	Minutes bool
+
+	rect  image.Rectangle
+	hands []hand
+}
+
+// Reset makes the next Draw render the whole face, e.g. after the canvas
+// was cleared
+func (a *Analog) Reset() {
+	a.rect = image.Rectangle{}
+	a.hands = nil
+}
+
+// geometry returns the center and radius of the dial in r
+func geometry(r image.Rectangle) (image.Point, int) {
+	radius := (min(r.Dx(), r.Dy()) - 1) / 2
+	return image.Pt(r.Min.X+r.Dx()/2, r.Min.Y+r.Dy()/2), radius
+}
+
+// handsAt returns the hands showing t for a dial of the given radius. Hands
+// stay inside the tick marks, so moving them never touches the face.
+func (a *Analog) handsAt(t time.Time, radius int) []hand {
+	R := float64(radius)
+	h, m, s := t.Clock()
+	hands := []hand{
+		{pos: h%12*60 + m, steps: 720, length: R * 0.5, width: max(1, R/8)},
+		{pos: m, steps: 60, length: min(R*0.8, R-7), width: max(1, R/14)},
+	}
+	if a.Seconds {
+		hands = append(hands, hand{pos: s, steps: 60, length: R - 6, width: 1})
+	}
+	return hands
+}
+
+// Draw renders the face showing t into r and returns the area that changed.
+// The first call, or a call with another rectangle, erases r and draws the
+// whole face; later calls only move the hands that changed.
+func (a *Analog) Draw(dst draw.Image, r image.Rectangle, t time.Time) image.Rectangle {
+	c, radius := geometry(r)
+	if radius < 8 {
+		erase(dst, r)
+		a.Reset()
+		return r
+	}
+	hands := a.handsAt(t, radius)
+
+	var dirty image.Rectangle
+	if r != a.rect || len(hands) != len(a.hands) {
+		erase(dst, r)
+		a.face(dst, c, radius)
+		dirty = r
+	} else {
+		// Erase the hands that moved
+		for i, h := range a.hands {
+			if h != hands[i] {
+				dirty = union(dirty, h.draw(dst, c, gfx.Off))
+			}
+		}
+		if dirty.Empty() {
+			return dirty
+		}
+	}
+
+	// Draw every hand, since erasing one may have cut through the others
+	for i, h := range hands {
+		area := h.draw(dst, c, gfx.On)
+		if i >= len(a.hands) || h != a.hands[i] {
+			dirty = union(dirty, area)
+		}
+	}
+	gfx.Circle(dst, c, max(1, radius/16), gfx.Fill(gfx.On))
+
+	a.rect = r
+	a.hands = hands
+	return dirty.Intersect(r)
+}
+
+// face draws the dial outline and tick marks
+func (a *Analog) face(dst draw.Image, c image.Point, radius int) {
+	gfx.Circle(dst, c, radius, gfx.Stroke(gfx.On))
+
+	spoke := func(i int, from, to float64) {
+		angle := 2 * math.Pi * float64(i) / 60
+		dx, dy := math.Sin(angle), -math.Cos(angle)
+		gfx.Line(dst,
+			c.X+int(math.Round(dx*from)), c.Y+int(math.Round(dy*from)),
+			c.X+int(math.Round(dx*to)), c.Y+int(math.Round(dy*to)),
+			gfx.On)
+	}
+
+	R := float64(radius)
+	for i := 0; i < 60; i++ {
+		switch {
+		case i%15 == 0:
+			spoke(i, R-5, R-2)
+		case i%5 == 0:
+			spoke(i, R-3, R-2)
+		case a.Minutes:
+			spoke(i, R-2, R-2)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package clock
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func at(h, m, s int) time.Time {
+	return time.Date(2024, 1, 1, h, m, s, 0, time.UTC)
+}
+
+func TestAnalogFirstDraw(t *testing.T) {
+	r := image.Rect(10, 0, 74, 64)
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	img.Fill(true)
+
+	a := &Analog{Seconds: true}
+	if got := a.Draw(img, r, at(3, 0, 0)); got != r {
+		t.Errorf("Expected the first draw to cover %v, got %v", r, got)
+	}
+	if raster.Count(img, image.Rect(0, 0, 10, 64)) != 10*64 {
+		t.Error("Expected pixels outside the rectangle to be kept")
+	}
+	if raster.Count(img, r) == 0 || raster.Count(img, r) == r.Dx()*r.Dy() {
+		t.Error("Expected the rectangle to be erased and the face drawn")
+	}
+
+	// At 3:00 the minute hand points up and the hour hand right
+	c, _ := geometry(r)
+	if !img.GetPixel(c.X, c.Y-15) {
+		t.Error("Expected the minute hand above the hub")
+	}
+	if !img.GetPixel(c.X+10, c.Y) {
+		t.Error("Expected the hour hand right of the hub")
+	}
+	if img.GetPixel(c.X-10, c.Y) {
+		t.Error("Expected no hand left of the hub")
+	}
+}
+
+func TestAnalogUnchanged(t *testing.T) {
+	r := image.Rect(0, 0, 64, 64)
+	img := mono.New(r)
+	a := &Analog{}
+	a.Draw(img, r, at(10, 10, 5))
+	if got := a.Draw(img, r, at(10, 10, 30)); !got.Empty() {
+		t.Errorf("Expected nothing to change without a second hand, got %v", got)
+	}
+}
+
+func TestAnalogIncremental(t *testing.T) {
+	r := image.Rect(0, 0, 64, 64)
+	img := mono.New(r)
+	a := &Analog{Seconds: true, Minutes: true}
+	a.Draw(img, r, at(10, 9, 59))
+
+	for _, tm := range []time.Time{at(10, 10, 0), at(10, 10, 1), at(10, 59, 59), at(11, 0, 0)} {
+		dirty := a.Draw(img, r, tm)
+		if dirty.Empty() || dirty == r {
+			t.Errorf("Expected a partial redraw at %v, got %v", tm, dirty)
+		}
+
+		want := mono.New(r)
+		(&Analog{Seconds: true, Minutes: true}).Draw(want, r, tm)
+		if !same(img, want) {
+			t.Errorf("Expected the redraw at %v to match a full draw", tm)
+		}
+	}
+
+	// One second only moves the second hand, which is in the top right
+	// quarter between 0 and 15 seconds
+	a.Draw(img, r, at(1, 30, 5))
+	dirty := a.Draw(img, r, at(1, 30, 6))
+	c, _ := geometry(r)
+	if dirty.Min.X < c.X-2 || dirty.Max.Y > c.Y+3 {
+		t.Errorf("Expected only the second hand to be redrawn, got %v", dirty)
+	}
+}
+
+func TestAnalogReset(t *testing.T) {
+	r := image.Rect(0, 0, 64, 64)
+	img := mono.New(r)
+	a := &Analog{}
+	a.Draw(img, r, at(1, 2, 3))
+	img.Fill(false)
+	a.Reset()
+	if got := a.Draw(img, r, at(1, 2, 3)); got != r {
+		t.Errorf("Expected a full redraw after Reset, got %v", got)
+	}
+	if raster.Count(img, r) == 0 {
+		t.Error("Expected the face to be drawn again")
+	}
+}
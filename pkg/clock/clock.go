@@ -0,0 +1,27 @@
+// Package clock provides analog and seven-segment digital clock widgets that
+// render into a rectangle of a monochrome canvas. Widgets remember what they
+// drew, so redrawing them every second only touches the parts that changed.
+package clock
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+)
+
+// erase darkens the whole rectangle
+func erase(dst draw.Image, r image.Rectangle) {
+	gfx.Rect(dst, r, gfx.Fill(gfx.Off))
+}
+
+// union returns the smallest rectangle containing a and b, ignoring empty ones
+func union(a, b image.Rectangle) image.Rectangle {
+	if a.Empty() {
+		return b
+	}
+	if b.Empty() {
+		return a
+	}
+	return a.Union(b)
+}
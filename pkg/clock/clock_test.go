@@ -0,0 +1,35 @@
+package clock
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// same reports whether a and b have the same pixels
+func same(a, b *mono.Image) bool {
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if a.GetPixel(x, y) != b.GetPixel(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestUnion(t *testing.T) {
+	a := image.Rect(0, 0, 2, 2)
+	b := image.Rect(5, 5, 6, 6)
+	if got := union(image.Rectangle{}, b); got != b {
+		t.Errorf("Expected %v, got %v", b, got)
+	}
+	if got := union(a, image.Rectangle{}); got != a {
+		t.Errorf("Expected %v, got %v", a, got)
+	}
+	if got := union(a, b); got != image.Rect(0, 0, 6, 6) {
+		t.Errorf("Expected %v, got %v", image.Rect(0, 0, 6, 6), got)
+	}
+}
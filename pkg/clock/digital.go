@@ -0,0 +1,186 @@
+package clock
+
+import (
+	"image"
+	"image/draw"
+	"time"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+)
+
+// Seven-segment bits, a being the top segment and going clockwise to f,
+// then g in the middle
+const (
+	segA = 1 << iota
+	segB
+	segC
+	segD
+	segE
+	segF
+	segG
+)
+
+// digitSegments lists the lit segments of the digits 0 to 9
+var digitSegments = [10]int{
+	segA | segB | segC | segD | segE | segF,
+	segB | segC,
+	segA | segB | segD | segE | segG,
+	segA | segB | segC | segD | segG,
+	segB | segC | segF | segG,
+	segA | segC | segD | segF | segG,
+	segA | segC | segD | segE | segF | segG,
+	segA | segB | segC,
+	segA | segB | segC | segD | segE | segF | segG,
+	segA | segB | segC | segD | segF | segG,
+}
+
+// Digital is a large seven-segment clock showing hours and minutes, and
+// optionally seconds
+type Digital struct {
+	// Seconds adds the seconds after a second colon
+	Seconds bool
+	// Hour12 shows hours from 1 to 12 instead of 0 to 23, without a leading zero
+	Hour12 bool
+	// Blink hides the colons on odd seconds
+	Blink bool
+
+	rect  image.Rectangle
+	cells []string
+}
+
+// Reset makes the next Draw render every digit, e.g. after the canvas was
+// cleared
+func (d *Digital) Reset() {
+	d.rect = image.Rectangle{}
+	d.cells = nil
+}
+
+// cellsAt returns what each cell shows at t: a digit, ":" for a colon, or a
+// space for a blank
+func (d *Digital) cellsAt(t time.Time) []string {
+	h, m, s := t.Clock()
+	if d.Hour12 {
+		h = (h+11)%12 + 1
+	}
+	colon := ":"
+	if d.Blink && s%2 == 1 {
+		colon = " "
+	}
+
+	digits := func(v int) []string {
+		return []string{string(rune('0' + v/10)), string(rune('0' + v%10))}
+	}
+	cells := digits(h)
+	if d.Hour12 && h < 10 {
+		cells[0] = " "
+	}
+	cells = append(cells, colon)
+	cells = append(cells, digits(m)...)
+	if d.Seconds {
+		cells = append(cells, colon)
+		cells = append(cells, digits(s)...)
+	}
+	return cells
+}
+
+// layout returns the rectangle of every cell and the segment thickness, the
+// clock being centered in r
+func layout(r image.Rectangle, cells []string) ([]image.Rectangle, int) {
+	// Every third cell is a colon
+	colons := (len(cells) - 2) / 3
+	digits := len(cells) - colons
+
+	h := r.Dy()
+	th := max(1, h/10)
+	gap := th
+	colonW := th * 3
+	dw := (r.Dx() - colons*colonW - (len(cells)-1)*gap) / digits
+	dw = min(dw, h/2+th)
+	if dw < 3*th {
+		return nil, 0
+	}
+
+	total := digits*dw + colons*colonW + (len(cells)-1)*gap
+	x := r.Min.X + (r.Dx()-total)/2
+	rects := make([]image.Rectangle, len(cells))
+	for i := range cells {
+		w := dw
+		if i%3 == 2 {
+			w = colonW
+		}
+		rects[i] = image.Rect(x, r.Min.Y, x+w, r.Max.Y)
+		x += w + gap
+	}
+	return rects, th
+}
+
+// Draw renders the clock showing t into r and returns the area that changed.
+// The first call, or a call with another rectangle, erases r and draws every
+// digit; later calls only redraw the digits and colons that changed.
+func (d *Digital) Draw(dst draw.Image, r image.Rectangle, t time.Time) image.Rectangle {
+	cells := d.cellsAt(t)
+	rects, th := layout(r, cells)
+
+	full := r != d.rect || len(cells) != len(d.cells)
+	if full {
+		erase(dst, r)
+	}
+	d.rect = r
+	if rects == nil {
+		d.cells = cells
+		return r
+	}
+
+	var dirty image.Rectangle
+	if full {
+		dirty = r
+	}
+	for i, c := range cells {
+		if !full && c == d.cells[i] {
+			continue
+		}
+		erase(dst, rects[i])
+		if i%3 == 2 {
+			if c == ":" {
+				colon(dst, rects[i], th)
+			}
+		} else if c != " " {
+			digit(dst, rects[i], th, digitSegments[c[0]-'0'])
+		}
+		dirty = union(dirty, rects[i])
+	}
+
+	d.cells = cells
+	return dirty
+}
+
+// digit draws the lit segments of a digit filling r
+func digit(dst draw.Image, r image.Rectangle, th, segments int) {
+	x0, y0, x1, y1 := r.Min.X, r.Min.Y, r.Max.X, r.Max.Y
+	// Top of the middle segment
+	ym := y0 + (r.Dy()-th)/2
+
+	// In the order of the segment bits
+	rects := [...]image.Rectangle{
+		image.Rect(x0+th, y0, x1-th, y0+th),
+		image.Rect(x1-th, y0+th, x1, ym),
+		image.Rect(x1-th, ym+th, x1, y1-th),
+		image.Rect(x0+th, y1-th, x1-th, y1),
+		image.Rect(x0, ym+th, x0+th, y1-th),
+		image.Rect(x0, y0+th, x0+th, ym),
+		image.Rect(x0+th, ym, x1-th, ym+th),
+	}
+	for i, sr := range rects {
+		if segments&(1<<i) != 0 {
+			gfx.Rect(dst, sr, gfx.Fill(gfx.On))
+		}
+	}
+}
+
+// colon draws two dots centered in r at a third and two thirds of its height
+func colon(dst draw.Image, r image.Rectangle, th int) {
+	x := r.Min.X + (r.Dx()-th)/2
+	for _, y := range []int{r.Min.Y + r.Dy()/3 - th/2, r.Min.Y + r.Dy()*2/3 - th/2} {
+		gfx.Rect(dst, image.Rect(x, y, x+th, y+th), gfx.Fill(gfx.On))
+	}
+}
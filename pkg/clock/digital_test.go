@@ -0,0 +1,91 @@
+package clock
+
+import (
+	"image"
+	"reflect"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestDigitalCells(t *testing.T) {
+	for _, tc := range []struct {
+		d    Digital
+		h    int
+		s    int
+		want []string
+	}{
+		{Digital{}, 13, 0, []string{"1", "3", ":", "0", "5"}},
+		{Digital{Hour12: true}, 13, 0, []string{" ", "1", ":", "0", "5"}},
+		{Digital{Hour12: true}, 0, 0, []string{"1", "2", ":", "0", "5"}},
+		{Digital{Seconds: true}, 9, 7, []string{"0", "9", ":", "0", "5", ":", "0", "7"}},
+		{Digital{Seconds: true, Blink: true}, 9, 7, []string{"0", "9", " ", "0", "5", " ", "0", "7"}},
+	} {
+		if got := tc.d.cellsAt(at(tc.h, 5, tc.s)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Expected %q, got %q", tc.want, got)
+		}
+	}
+}
+
+func TestDigitalDigits(t *testing.T) {
+	r := image.Rect(0, 0, 128, 40)
+	cells := []string{"8", "8", ":", "8", "8"}
+	rects, th := layout(r, cells)
+	if th != 4 {
+		t.Errorf("Expected segments 4 pixels thick, got %d", th)
+	}
+
+	img := mono.New(r)
+	(&Digital{}).Draw(img, r, at(18, 11, 0))
+
+	// 1 lights the right segments only, 8 all of them
+	one, eight := rects[0], rects[1]
+	if raster.Count(img, image.Rect(one.Min.X, one.Min.Y, one.Max.X-th, one.Max.Y)) != 0 {
+		t.Error("Expected 1 to leave the left of its cell dark")
+	}
+	if raster.Count(img, eight) <= raster.Count(img, one)*2 {
+		t.Error("Expected 8 to light more segments than 1")
+	}
+	if raster.Count(img, rects[2]) != 2*th*th {
+		t.Errorf("Expected two colon dots, got %d pixels", raster.Count(img, rects[2]))
+	}
+}
+
+func TestDigitalIncremental(t *testing.T) {
+	r := image.Rect(0, 10, 128, 50)
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	d := &Digital{Seconds: true}
+	if got := d.Draw(img, r, at(9, 59, 58)); got != r {
+		t.Errorf("Expected the first draw to cover %v, got %v", r, got)
+	}
+	if got := d.Draw(img, r, at(9, 59, 58)); !got.Empty() {
+		t.Errorf("Expected nothing to change, got %v", got)
+	}
+
+	rects, _ := layout(r, d.cellsAt(at(0, 0, 0)))
+	if got := d.Draw(img, r, at(9, 59, 59)); got != rects[7] {
+		t.Errorf("Expected only the last digit to change, got %v", got)
+	}
+	if got := d.Draw(img, r, at(10, 0, 0)); got != rects[0].Union(rects[7]) {
+		t.Errorf("Expected every digit to change, got %v", got)
+	}
+
+	want := mono.New(img.Bounds())
+	(&Digital{Seconds: true}).Draw(want, r, at(10, 0, 0))
+	if !same(img, want) {
+		t.Error("Expected the redraw to match a full draw")
+	}
+}
+
+func TestDigitalTooSmall(t *testing.T) {
+	r := image.Rect(0, 0, 10, 20)
+	img := mono.New(r)
+	img.Fill(true)
+	if got := (&Digital{}).Draw(img, r, at(1, 2, 3)); got != r {
+		t.Errorf("Expected %v, got %v", r, got)
+	}
+	if raster.Count(img, r) != 0 {
+		t.Error("Expected a rectangle too small for the digits to be left blank")
+	}
+}
@@ -0,0 +1,143 @@
+package display
+
+import "fmt"
+
+// Controller identifies the OLED controller chip behind a display
+type Controller int
+
+const (
+	// ControllerSH1106 is the SH1106, 132x64 RAM shown from column 2, page addressing only
+	ControllerSH1106 Controller = iota
+	// ControllerSSD1306 is the SSD1306, 128x64 RAM with horizontal addressing and a charge pump
+	ControllerSSD1306
+	// ControllerSH1107 is the SH1107, 128x128 RAM with 16 pages and a 7-bit COM offset
+	ControllerSH1107
+)
+
+// String implements fmt.Stringer
+func (c Controller) String() string {
+	switch c {
+	case ControllerSH1106:
+		return "SH1106"
+	case ControllerSSD1306:
+		return "SSD1306"
+	case ControllerSH1107:
+		return "SH1107"
+	}
+	return fmt.Sprintf("Controller(%d)", int(c))
+}
+
+// columnOffset returns the first RAM column shown on the panel
+func (c Controller) columnOffset() int {
+	if c == ControllerSH1106 {
+		return 2
+	}
+	return 0
+}
+
+// displayOffset returns the COM offset that maps RAM row 0 to the top of the panel
+// when rows of its height rows are driven.
+// SH1107 modules with only 64 rows are wired to COM32-COM95 and need an offset of 0x60.
+// The SSD1306 scans remapped from COM[rows-1] down to COM0, so fewer rows would land
+// on the bottom of the panel unless the scan is shifted back by the rows left out.
+func (c Controller) displayOffset(height, rows int) int {
+	switch {
+	case c == ControllerSH1107 && height < 128:
+		return 0x60
+	case c == ControllerSSD1306:
+		return (rows - height + 64) % 64
+	}
+	return 0
+}
+
+// minMultiplex returns the smallest number of rows the controller can drive
+func (c Controller) minMultiplex() int {
+	if c == ControllerSSD1306 {
+		return 16
+	}
+	return 1
+}
+
+// horizontal reports whether the whole frame can be streamed with
+// horizontal addressing instead of one page at a time
+func (c Controller) horizontal() bool {
+	return c == ControllerSSD1306
+}
+
+// initCommands returns the command sequence that brings the controller up
+// for a panel of width x height pixels
+func (c Controller) initCommands(width, height int) []byte {
+	switch c {
+	case ControllerSH1107:
+		return []byte{
+			0xAE,       // Turn off OLED panel
+			0xDC, 0x00, // Set display start line, two bytes on the SH1107
+			0x81, 0x4F, // Set contrast control register
+			0x20,                   // Set Page Addressing Mode
+			0xA0,                   // Set SEG/Column mapping
+			0xC0,                   // Set COM/Row scan direction
+			0xA8, byte(height - 1), // Set multiplex ratio
+			0xD3, byte(c.displayOffset(height, height)), // Set display offset
+			0xD5, 0x51, // Set display clock divide ratio/oscillator frequency
+			0xD9, 0x22, // Set pre-charge period
+			0xDB, 0x35, // Set VCOM Deselect Level
+			0xAD, 0x81, // Enable internal DC-DC converter
+			0xA4, // Disable Entire Display On
+			0xA6, // Disable Inverse Display On
+			0xAF, // Turn on OLED panel
+		}
+	case ControllerSSD1306:
+		comPins := byte(0x12) // Alternative COM pin configuration
+		if height <= 32 {
+			comPins = 0x02 // Sequential COM pin configuration
+		}
+
+		return []byte{
+			0xAE,       // Turn off OLED panel
+			0xD5, 0x80, // Set display clock divide ratio/oscillator frequency
+			0xA8, byte(height - 1), // Set multiplex ratio
+			0xD3, 0x00, // Set display offset, not offset
+			0x40,       // Set start line address
+			0x8D, 0x14, // Enable charge pump
+			0x20, 0x00, // Set Horizontal Addressing Mode
+			0xA1,          // Set SEG/Column mapping, column 127 mapped to SEG0
+			0xC8,          // Set COM/Row scan direction, remapped
+			0xDA, comPins, // Set com pins hardware configuration
+			0x81, 0xCF, // Set contrast control register
+			0xD9, 0xF1, // Set pre-charge period
+			0xDB, 0x40, // Set VCOM Deselect Level
+			0x2E, // Deactivate scroll
+			0xA4, // Disable Entire Display On
+			0xA6, // Disable Inverse Display On
+			0xAF, // Turn on OLED panel
+		}
+	}
+
+	return []byte{
+		0xAE, // Turn off OLED panel
+		0x02, // Set low column address
+		0x10, // Set high column address
+		0x40, // Set start line address
+		0x81, // Set contrast control register
+		0xA0, // Set SEG/Column mapping
+		0xC0, // Set COM/Row scan direction
+		0xA6, // Set normal display
+		0xA8, // Set multiplex ratio (1 to 64)
+		0x3F, // 1/64 duty
+		0xD3, // Set display offset
+		0x00, // Not offset
+		0xD5, // Set display clock divide ratio/oscillator frequency
+		0x80, // Set divide ratio, Set Clock as 100 Frames/Sec
+		0xD9, // Set pre-charge period
+		0xF1, // Set Pre-Charge as 15 Clocks & Discharge as 1 Clock
+		0xDA, // Set com pins hardware configuration
+		0x12,
+		0xDB, // Set vcomh
+		0x40, // Set VCOM Deselect Level
+		0x20, // Set Page Addressing Mode
+		0x02,
+		0xA4, // Disable Entire Display On
+		0xA6, // Disable Inverse Display On
+		0xAF, // Turn on OLED panel
+	}
+}
@@ -0,0 +1,344 @@
+package display
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sync"
+)
+
+// Emulator is a Transport that interprets the controller command stream in
+// memory instead of driving a panel. It models the display RAM, page and
+// column addressing, start line, multiplex ratio, display offset, COM scan
+// direction and the on/off and inverse states, which is enough to render what
+// a real panel would show. Segment remap and COM pin configuration are taken
+// to be the ones the drivers send, which modules are wired for. It is meant
+// for tests and for replaying traces offline.
+type Emulator struct {
+	mu sync.Mutex
+
+	ctrl      Controller
+	width     int
+	height    int
+	colOffset int
+	ramCols   int
+	ramPages  int
+	argCount  map[byte]int
+
+	ram  []byte
+	page int
+	col  int
+
+	// Horizontal addressing state, SSD1306 only
+	horizontal bool
+	colStart   int
+	colEnd     int
+	pageStart  int
+	pageEnd    int
+
+	pending []byte
+	want    int
+
+	on        bool
+	inverse   bool
+	allOn     bool
+	contrast  byte
+	startLine int
+	mux       int
+	offset    int
+	comRemap  bool
+}
+
+var (
+	_ Transport = (*Emulator)(nil)
+	_ Resetter  = (*Emulator)(nil)
+)
+
+// sh1106Args lists the SH1106 commands that take argument bytes
+var sh1106Args = map[byte]int{
+	0x20: 1, // Addressing mode (ignored, sent by the init sequence)
+	0x81: 1, // Contrast
+	0x8D: 1, // Charge pump
+	0xA8: 1, // Multiplex ratio
+	0xAD: 1, // DC-DC control
+	0xD3: 1, // Display offset
+	0xD5: 1, // Clock divide ratio
+	0xD9: 1, // Pre-charge period
+	0xDA: 1, // COM pins configuration
+	0xDB: 1, // VCOM deselect level
+}
+
+// ssd1306Args lists the SSD1306 commands that take argument bytes
+var ssd1306Args = map[byte]int{
+	0x20: 1, // Memory addressing mode
+	0x21: 2, // Column address range
+	0x22: 2, // Page address range
+	0x26: 6, // Right horizontal scroll setup
+	0x27: 6, // Left horizontal scroll setup
+	0x29: 5, // Vertical and right horizontal scroll setup
+	0x2A: 5, // Vertical and left horizontal scroll setup
+	0x81: 1, // Contrast
+	0x8D: 1, // Charge pump
+	0xA3: 2, // Vertical scroll area
+	0xA8: 1, // Multiplex ratio
+	0xD3: 1, // Display offset
+	0xD5: 1, // Clock divide ratio
+	0xD9: 1, // Pre-charge period
+	0xDA: 1, // COM pins configuration
+	0xDB: 1, // VCOM deselect level
+}
+
+// sh1107Args lists the SH1107 commands that take argument bytes.
+// Unlike the SSD1306, 0x20 and 0x21 select the addressing mode on their own.
+var sh1107Args = map[byte]int{
+	0x81: 1, // Contrast
+	0xA8: 1, // Multiplex ratio
+	0xAD: 1, // DC-DC control
+	0xD3: 1, // Display offset
+	0xD5: 1, // Clock divide ratio
+	0xD9: 1, // Pre-charge period
+	0xDB: 1, // VCOM deselect level
+	0xDC: 1, // Display start line
+}
+
+// NewSH1106Emulator creates an emulator for a SH1106 panel of the given size.
+// The SH1106 has 132 columns of RAM and the panel shows columns 2 to 129.
+func NewSH1106Emulator(opts *Options) *Emulator {
+	return newEmulator(ControllerSH1106, opts, 132, 8, sh1106Args)
+}
+
+// NewSSD1306Emulator creates an emulator for a SSD1306 panel of the given size
+func NewSSD1306Emulator(opts *Options) *Emulator {
+	return newEmulator(ControllerSSD1306, opts, 128, 8, ssd1306Args)
+}
+
+// NewSH1107Emulator creates an emulator for a SH1107 panel of the given size
+func NewSH1107Emulator(opts *Options) *Emulator {
+	return newEmulator(ControllerSH1107, opts, 128, 16, sh1107Args)
+}
+
+// newEmulator creates an emulator with the given RAM geometry and command set
+func newEmulator(ctrl Controller, opts *Options, ramCols, ramPages int, args map[byte]int) *Emulator {
+	e := &Emulator{
+		ctrl:      ctrl,
+		width:     opts.Width,
+		height:    opts.Height,
+		colOffset: ctrl.columnOffset(),
+		ramCols:   ramCols,
+		ramPages:  ramPages,
+		argCount:  args,
+	}
+	e.ram = make([]byte, e.ramCols*e.ramPages)
+	e.reset()
+	return e
+}
+
+// Reset puts the emulated controller back into its power-on state.
+// Like on real hardware the RAM content is preserved.
+func (e *Emulator) Reset() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.reset()
+	return nil
+}
+
+// reset restores the power-on register values
+func (e *Emulator) reset() {
+	e.page = 0
+	e.col = 0
+	e.horizontal = false
+	e.colStart = 0
+	e.colEnd = e.ramCols - 1
+	e.pageStart = 0
+	e.pageEnd = e.ramPages - 1
+	e.pending = e.pending[:0]
+	e.want = 0
+	e.on = false
+	e.inverse = false
+	e.allOn = false
+	e.contrast = 0x80
+	e.startLine = 0
+	e.mux = e.ramPages*8 - 1
+	e.offset = 0
+	e.comRemap = false
+}
+
+// Command interprets command bytes
+func (e *Emulator) Command(cmds ...byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, b := range cmds {
+		if e.want > 0 {
+			e.pending = append(e.pending, b)
+			e.want--
+			if e.want == 0 {
+				e.exec(e.pending[0], e.pending[1:])
+				e.pending = e.pending[:0]
+			}
+			continue
+		}
+
+		if n := e.argCount[b]; n > 0 {
+			e.pending = append(e.pending, b)
+			e.want = n
+			continue
+		}
+		e.exec(b, nil)
+	}
+
+	return nil
+}
+
+// exec applies a complete command
+func (e *Emulator) exec(cmd byte, args []byte) {
+	switch {
+	case cmd <= 0x0F:
+		e.col = e.col&0xF0 | int(cmd&0x0F)
+	case cmd >= 0x10 && cmd <= 0x1F:
+		e.col = e.col&0x0F | int(cmd&0x0F)<<4
+	case cmd >= 0x40 && cmd <= 0x7F && e.ctrl != ControllerSH1107:
+		e.startLine = int(cmd & 0x3F)
+	case cmd == 0xDC:
+		e.startLine = int(args[0] & 0x7F)
+	case cmd >= 0xB0 && cmd <= 0xBF:
+		e.page = int(cmd & 0x0F)
+	case cmd == 0x20 && e.ctrl == ControllerSSD1306:
+		e.horizontal = args[0]&0x03 == 0x00
+	case cmd == 0x21 && e.ctrl == ControllerSSD1306:
+		e.colStart = int(args[0] & 0x7F)
+		e.colEnd = int(args[1] & 0x7F)
+		e.col = e.colStart
+	case cmd == 0x22 && e.ctrl == ControllerSSD1306:
+		e.pageStart = int(args[0] & 0x07)
+		e.pageEnd = int(args[1] & 0x07)
+		e.page = e.pageStart
+	case cmd == 0x81:
+		e.contrast = args[0]
+	case cmd == 0xA4, cmd == 0xA5:
+		e.allOn = cmd == 0xA5
+	case cmd == 0xA6, cmd == 0xA7:
+		e.inverse = cmd == 0xA7
+	case cmd == 0xA8:
+		e.mux = int(args[0] & 0x7F)
+	case cmd == 0xAE, cmd == 0xAF:
+		e.on = cmd == 0xAF
+	case cmd == 0xD3:
+		e.offset = int(args[0] & 0x7F)
+	case cmd == 0xC0, cmd == 0xC8:
+		e.comRemap = cmd == 0xC8
+	}
+}
+
+// Data writes display RAM at the current address
+func (e *Emulator) Data(data []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, b := range data {
+		if e.page < e.ramPages && e.col < e.ramCols {
+			e.ram[e.page*e.ramCols+e.col] = b
+		}
+
+		if !e.horizontal {
+			if e.col < e.ramCols {
+				e.col++
+			}
+			continue
+		}
+
+		// Horizontal addressing wraps to the next page of the range
+		if e.col++; e.col > e.colEnd {
+			e.col = e.colStart
+			if e.page++; e.page > e.pageEnd {
+				e.page = e.pageStart
+			}
+		}
+	}
+
+	return nil
+}
+
+// com returns the COM output wired to panel row y. SH1107 panels with fewer
+// rows than its 128 outputs are wired to the middle ones, COM32 to COM95 on
+// 64 row modules. SSD1306 modules are wired for the remapped scan, with the
+// top row on the last COM output of the panel.
+func (e *Emulator) com(y int) int {
+	switch e.ctrl {
+	case ControllerSH1107:
+		return (e.ramPages*8-e.height)/2 + y
+	case ControllerSSD1306:
+		return e.height - 1 - y
+	}
+	return y
+}
+
+// lit reports whether the panel pixel at x, y is lit
+func (e *Emulator) lit(x, y int) bool {
+	if !e.on {
+		return false
+	}
+
+	// The row counter runs from 0 to the multiplex ratio, the display offset
+	// shifting which COM output it drives. The remapped scan drives the
+	// outputs from COM[mux] down to COM0.
+	rows := e.ramPages * 8
+	r := (e.com(y) + e.offset) % rows
+	if e.comRemap {
+		r = ((e.mux-e.com(y)-e.offset)%rows + rows) % rows
+	}
+	if r > e.mux {
+		return false
+	}
+	if e.allOn {
+		return true
+	}
+
+	row := (r + e.startLine) % rows
+	col := x + e.colOffset
+	on := col < e.ramCols && e.ram[(row/8)*e.ramCols+col]&(1<<(row%8)) != 0
+
+	return on != e.inverse
+}
+
+// Image renders what the panel currently shows, lit pixels are white
+func (e *Emulator) Image() *image.Gray {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	img := image.NewGray(image.Rect(0, 0, e.width, e.height))
+	for y := 0; y < e.height; y++ {
+		for x := 0; x < e.width; x++ {
+			if e.lit(x, y) {
+				img.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+
+	return img
+}
+
+// Lit reports whether the panel pixel at x, y is lit
+func (e *Emulator) Lit(x, y int) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if x < 0 || x >= e.width || y < 0 || y >= e.height {
+		return false
+	}
+	return e.lit(x, y)
+}
+
+// Contrast returns the last contrast value set
+func (e *Emulator) Contrast() byte {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.contrast
+}
+
+// String implements fmt.Stringer
+func (e *Emulator) String() string {
+	return fmt.Sprintf("%sEmulator{%dx%d}", e.ctrl, e.width, e.height)
+}
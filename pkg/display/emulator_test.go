@@ -0,0 +1,76 @@
+package display
+
+import (
+	"testing"
+)
+
+func newEmulatedSH1106(t *testing.T) (*SH1106, *Emulator) {
+	t.Helper()
+
+	opts := &Options{Width: 128, Height: 64}
+	emu := NewSH1106Emulator(opts)
+	dev, err := NewSH1106(emu, opts)
+	if err != nil {
+		t.Fatalf("Failed to create SH1106 on emulator: %v", err)
+	}
+
+	return dev, emu
+}
+
+func TestEmulatorInit(t *testing.T) {
+	_, emu := newEmulatedSH1106(t)
+
+	if !emu.on {
+		t.Error("Display should be on after init")
+	}
+
+	if emu.mux != 63 {
+		t.Errorf("Expected multiplex ratio 63, got %d", emu.mux)
+	}
+
+	if len(emu.pending) != 0 {
+		t.Errorf("Expected no pending command bytes, got %v", emu.pending)
+	}
+}
+
+func TestEmulatorUpdate(t *testing.T) {
+	dev, emu := newEmulatedSH1106(t)
+
+	dev.Clear()
+	dev.SetPixel(10, 20, true)
+	dev.SetPixel(127, 63, true)
+	if err := dev.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if !emu.Lit(10, 20) {
+		t.Error("Expected pixel (10, 20) to be lit")
+	}
+
+	if !emu.Lit(127, 63) {
+		t.Error("Expected pixel (127, 63) to be lit")
+	}
+
+	if emu.Lit(11, 20) {
+		t.Error("Expected pixel (11, 20) to be dark")
+	}
+}
+
+func TestEmulatorInverseAndOff(t *testing.T) {
+	dev, emu := newEmulatedSH1106(t)
+
+	dev.Clear()
+	if err := dev.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	emu.Command(0xA7)
+	if !emu.Lit(0, 0) {
+		t.Error("Expected pixel to be lit in inverse mode")
+	}
+
+	emu.Command(0xAE)
+	if emu.Lit(0, 0) {
+		t.Error("Expected pixel to be dark when display is off")
+	}
+}
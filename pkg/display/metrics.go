@@ -0,0 +1,114 @@
+package display
+
+import (
+	"expvar"
+	"time"
+)
+
+// Metrics receives statistics from a display driver.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// FrameFlushed is called after a frame was sent to the panel
+	FrameFlushed(bytes int, latency time.Duration)
+	// TransactionError is called when a write to the transport fails
+	TransactionError(err error)
+	// FPS is called after every flush with the current flush rate
+	FPS(fps float64)
+}
+
+// NopMetrics discards all statistics, it is the default
+type NopMetrics struct{}
+
+var _ Metrics = NopMetrics{}
+
+// FrameFlushed implements Metrics
+func (NopMetrics) FrameFlushed(int, time.Duration) {}
+
+// TransactionError implements Metrics
+func (NopMetrics) TransactionError(error) {}
+
+// FPS implements Metrics
+func (NopMetrics) FPS(float64) {}
+
+// latencyBuckets are the upper bounds of the flush latency histogram. Like
+// Prometheus histograms the buckets are cumulative: each counts the flushes
+// that took at most its limit, so le_inf counts them all.
+var latencyBuckets = []struct {
+	name  string
+	limit time.Duration
+}{
+	{"le_1ms", 1 * time.Millisecond},
+	{"le_2ms", 2 * time.Millisecond},
+	{"le_5ms", 5 * time.Millisecond},
+	{"le_10ms", 10 * time.Millisecond},
+	{"le_20ms", 20 * time.Millisecond},
+	{"le_50ms", 50 * time.Millisecond},
+	{"le_100ms", 100 * time.Millisecond},
+	{"le_inf", 1<<63 - 1},
+}
+
+// ExpvarMetrics publishes statistics with the expvar package, so they show up
+// under /debug/vars when the default HTTP mux is served
+type ExpvarMetrics struct {
+	m       *expvar.Map
+	frames  *expvar.Int
+	bytes   *expvar.Int
+	errors  *expvar.Int
+	fps     *expvar.Float
+	latency *expvar.Map
+}
+
+var _ Metrics = (*ExpvarMetrics)(nil)
+
+// NewExpvarMetrics publishes a map with the given name holding the frames,
+// bytes, errors and fps counters and the cumulative latency histogram. Like
+// expvar.Publish it panics if the name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	e := &ExpvarMetrics{
+		m:       expvar.NewMap(name),
+		frames:  new(expvar.Int),
+		bytes:   new(expvar.Int),
+		errors:  new(expvar.Int),
+		fps:     new(expvar.Float),
+		latency: new(expvar.Map),
+	}
+
+	for _, b := range latencyBuckets {
+		e.latency.Set(b.name, new(expvar.Int))
+	}
+
+	e.m.Set("frames", e.frames)
+	e.m.Set("bytes", e.bytes)
+	e.m.Set("errors", e.errors)
+	e.m.Set("fps", e.fps)
+	e.m.Set("latency", e.latency)
+
+	return e
+}
+
+// FrameFlushed implements Metrics
+func (e *ExpvarMetrics) FrameFlushed(bytes int, latency time.Duration) {
+	e.frames.Add(1)
+	e.bytes.Add(int64(bytes))
+
+	for _, b := range latencyBuckets {
+		if latency <= b.limit {
+			e.latency.Add(b.name, 1)
+		}
+	}
+}
+
+// TransactionError implements Metrics
+func (e *ExpvarMetrics) TransactionError(error) {
+	e.errors.Add(1)
+}
+
+// FPS implements Metrics
+func (e *ExpvarMetrics) FPS(fps float64) {
+	e.fps.Set(fps)
+}
+
+// Map returns the published expvar map
+func (e *ExpvarMetrics) Map() *expvar.Map {
+	return e.m
+}
@@ -0,0 +1,147 @@
+package display
+
+import (
+	"bytes"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	mu     sync.Mutex
+	frames int
+	bytes  int
+	errors int
+	fps    float64
+}
+
+func (m *recordingMetrics) FrameFlushed(bytes int, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.frames++
+	m.bytes += bytes
+}
+
+func (m *recordingMetrics) TransactionError(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors++
+}
+
+func (m *recordingMetrics) FPS(fps float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fps = fps
+}
+
+// failingTransport accepts the init sequence and then fails every write
+type failingTransport struct {
+	*Emulator
+	fail bool
+}
+
+func (f *failingTransport) Data(data []byte) error {
+	if f.fail {
+		return errors.New("bus error")
+	}
+	return nil
+}
+
+func TestMetricsFlush(t *testing.T) {
+	metrics := &recordingMetrics{}
+	opts := &Options{Width: 128, Height: 64, Metrics: metrics}
+	dev, err := NewSH1106(NewSH1106Emulator(opts), opts)
+	if err != nil {
+		t.Fatalf("Failed to create SH1106: %v", err)
+	}
+
+	for range 2 {
+		if err := dev.Update(); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+	}
+
+	if metrics.frames != 2 {
+		t.Errorf("Expected 2 frames, got %d", metrics.frames)
+	}
+
+	// 8 pages of 3 command bytes and 128 data bytes per frame
+	if expected := 2 * 8 * (3 + 128); metrics.bytes != expected {
+		t.Errorf("Expected %d bytes, got %d", expected, metrics.bytes)
+	}
+
+	if metrics.fps <= 0 || dev.FPS() != metrics.fps {
+		t.Errorf("Expected a positive FPS reported by both, got %v and %v", metrics.fps, dev.FPS())
+	}
+}
+
+func TestMetricsTransactionError(t *testing.T) {
+	var logs bytes.Buffer
+	metrics := &recordingMetrics{}
+	opts := &Options{
+		Width:   128,
+		Height:  64,
+		Metrics: metrics,
+		Logger:  slog.New(slog.NewTextHandler(&logs, nil)),
+	}
+
+	ft := &failingTransport{Emulator: NewSH1106Emulator(opts)}
+	dev, err := NewSH1106(ft, opts)
+	if err != nil {
+		t.Fatalf("Failed to create SH1106: %v", err)
+	}
+
+	ft.fail = true
+	if err := dev.Update(); err == nil {
+		t.Fatal("Expected Update to fail")
+	}
+
+	if metrics.errors != 1 || metrics.frames != 0 {
+		t.Errorf("Expected 1 error and no frames, got %d and %d", metrics.errors, metrics.frames)
+	}
+
+	if !strings.Contains(logs.String(), "display flush failed") {
+		t.Errorf("Expected flush failure to be logged, got %q", logs.String())
+	}
+}
+
+// expvarRuns makes the published names unique, since expvar names are global
+// to the process and tests may run more than once
+var expvarRuns atomic.Int32
+
+func TestExpvarMetrics(t *testing.T) {
+	m := NewExpvarMetrics(fmt.Sprintf("display_test_%d", expvarRuns.Add(1)))
+
+	m.FrameFlushed(100, 3*time.Millisecond)
+	m.FrameFlushed(50, time.Second)
+	m.TransactionError(errors.New("bus error"))
+	m.FPS(12.5)
+
+	vars := m.Map()
+	if got := vars.Get("frames").String(); got != "2" {
+		t.Errorf("Expected 2 frames, got %s", got)
+	}
+	if got := vars.Get("bytes").String(); got != "150" {
+		t.Errorf("Expected 150 bytes, got %s", got)
+	}
+	if got := vars.Get("errors").String(); got != "1" {
+		t.Errorf("Expected 1 error, got %s", got)
+	}
+	if got := vars.Get("fps").String(); got != "12.5" {
+		t.Errorf("Expected fps 12.5, got %s", got)
+	}
+
+	// Buckets are cumulative
+	latency := vars.Get("latency").(*expvar.Map)
+	for name, want := range map[string]string{"le_2ms": "0", "le_5ms": "1", "le_100ms": "1", "le_inf": "2"} {
+		if got := latency.Get(name).String(); got != want {
+			t.Errorf("Expected %s flushes in %s, got %s", want, name, got)
+		}
+	}
+}
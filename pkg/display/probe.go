@@ -0,0 +1,56 @@
+package display
+
+import (
+	"errors"
+
+	"periph.io/x/conn/v3/i2c"
+)
+
+// I2C addresses used by SH1106 and SSD1306 modules, selected by the SA0 pin
+const (
+	I2CAddr    uint16 = 0x3C
+	I2CAddrAlt uint16 = 0x3D
+)
+
+// ErrNoDisplay is returned by Probe when no display answers on the bus
+var ErrNoDisplay = errors.New("display: no display found on the I2C bus")
+
+// ssd1306StatusID is the chip ID the SSD1306 reports in the low bits of its
+// status byte. The SH1106 leaves those bits clear.
+const ssd1306StatusID = 0x06
+
+// Detect scans the I2C bus for a display and guesses its controller from the
+// status byte. When the bus does not allow reads the controller cannot be told
+// apart and ControllerSH1106 is assumed. The SH1107 reports the same status as
+// the SH1106 and is never detected.
+func Detect(b i2c.Bus) (uint16, Controller, error) {
+	for _, addr := range []uint16{I2CAddr, I2CAddrAlt} {
+		t := NewI2CTransport(b, addr)
+
+		// A NOP command is harmless and tells whether something acknowledges
+		if err := t.Command(0xE3); err != nil {
+			continue
+		}
+
+		status, err := t.Status()
+		if err == nil && status&0x1F == ssd1306StatusID {
+			return addr, ControllerSSD1306, nil
+		}
+		return addr, ControllerSH1106, nil
+	}
+
+	return 0, 0, ErrNoDisplay
+}
+
+// Probe scans the I2C bus and returns an initialized driver configured for
+// the detected controller. Use Controller on the result to find out which one.
+// Only SH1106 and SSD1306 modules are told apart; SH1107 modules are taken
+// for SH1106 ones and must be opened with NewSH1107I2C instead.
+func Probe(b i2c.Bus, opts *Options) (*SH1106, error) {
+	addr, ctrl, err := Detect(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDevice(NewI2CTransport(b, addr), ctrl, opts)
+}
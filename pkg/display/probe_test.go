@@ -0,0 +1,90 @@
+package display
+
+import (
+	"testing"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+// initOp is the write a driver for ctrl issues on init
+func initOp(addr uint16, ctrl Controller, opts *Options) i2ctest.IO {
+	w := append([]byte{i2cControlCommand}, ctrl.initCommands(opts.Width, opts.Height)...)
+	return i2ctest.IO{Addr: addr, W: w}
+}
+
+func TestProbeSSD1306(t *testing.T) {
+	opts := &Options{Width: 128, Height: 64}
+	bus := &i2ctest.Playback{
+		DontPanic: true,
+		Ops: []i2ctest.IO{
+			{Addr: I2CAddr, W: []byte{i2cControlCommand, 0xE3}},
+			{Addr: I2CAddr, R: []byte{0x46}}, // Display off, SSD1306 ID
+			initOp(I2CAddr, ControllerSSD1306, opts),
+		},
+	}
+
+	dev, err := Probe(bus, opts)
+	if err != nil {
+		t.Fatalf("Probe failed: %v", err)
+	}
+
+	if dev.Controller() != ControllerSSD1306 {
+		t.Errorf("Expected controller SSD1306, got %s", dev.Controller())
+	}
+
+	if err := bus.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProbeSH1106AltAddress(t *testing.T) {
+	opts := &Options{Width: 128, Height: 64}
+	bus := &i2ctest.Playback{
+		DontPanic: true,
+		Ops: []i2ctest.IO{
+			// Nothing answers at 0x3C, the first write fails
+			{Addr: I2CAddrAlt, W: []byte{i2cControlCommand, 0xE3}},
+			{Addr: I2CAddrAlt, R: []byte{0x40}}, // Display off, no ID bits
+			initOp(I2CAddrAlt, ControllerSH1106, opts),
+		},
+	}
+
+	dev, err := Probe(bus, opts)
+	if err != nil {
+		t.Fatalf("Probe failed: %v", err)
+	}
+
+	if dev.Controller() != ControllerSH1106 {
+		t.Errorf("Expected controller SH1106, got %s", dev.Controller())
+	}
+
+	if err := bus.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDetectWithoutStatusRead(t *testing.T) {
+	bus := &i2ctest.Playback{
+		DontPanic: true,
+		Ops: []i2ctest.IO{
+			{Addr: I2CAddr, W: []byte{i2cControlCommand, 0xE3}},
+		},
+	}
+
+	addr, ctrl, err := Detect(bus)
+	if err != nil {
+		t.Fatalf("Detect failed: %v", err)
+	}
+
+	if addr != I2CAddr || ctrl != ControllerSH1106 {
+		t.Errorf("Expected SH1106 at 0x3C, got %s at 0x%02X", ctrl, addr)
+	}
+}
+
+func TestProbeNoDisplay(t *testing.T) {
+	bus := &i2ctest.Playback{DontPanic: true}
+
+	if _, err := Probe(bus, &Options{Width: 128, Height: 64}); err != ErrNoDisplay {
+		t.Errorf("Expected ErrNoDisplay, got %v", err)
+	}
+}
@@ -6,164 +6,211 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"image/draw"
+	"log/slog"
 	"time"
 
-	"periph.io/x/conn/v3"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+
 	"periph.io/x/conn/v3/display"
 	"periph.io/x/conn/v3/gpio"
-	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/i2c"
 	"periph.io/x/conn/v3/spi"
 )
 
-var _ display.Drawer = (*SH1106)(nil)
+var (
+	_ display.Drawer = (*SH1106)(nil)
+	_ draw.Image     = (*SH1106)(nil)
+)
 
 // SH1106 driver for OLED displays
 type SH1106 struct {
-	c   conn.Conn
-	dc  gpio.PinOut
-	rst gpio.PinOut
-	cs  gpio.PinOut
-
-	rect   image.Rectangle
-	buffer []byte
+	t    Transport
+	ctrl Controller
+
+	rect       image.Rectangle
+	buffer     *mono.Image
+	activeRows int
+	dither     mono.Dither
+
+	logger    *slog.Logger
+	metrics   Metrics
+	sent      int
+	lastFlush time.Time
+	fps       float64
 }
 
 // Options defines the configuration options for the SH1106 device
 type Options struct {
 	Width  int
 	Height int
+
+	// Polarity selects which source color lights a pixel in Draw and which
+	// color lit pixels have in snapshots. The zero value is mono.LitIsWhite.
+	Polarity mono.Polarity
+
+	// Dither selects how Draw turns gray levels into pixels. The zero value
+	// is mono.Threshold, a plain 50% threshold.
+	Dither mono.Dither
+
+	// Logger receives flush failures and debug traces. Nil disables logging.
+	Logger *slog.Logger
+
+	// Metrics receives flush statistics. Nil disables metrics.
+	Metrics Metrics
 }
 
 // NewSH1106SPI creates a new SH1106 display driver for SPI communication
 func NewSH1106SPI(p spi.Port, dc, rst, cs gpio.PinOut, opts *Options) (*SH1106, error) {
-	if dc == nil || rst == nil || cs == nil {
-		return nil, errors.New("display: dc, rst, and cs pins are required")
-	}
-
-	speed := physic.Frequency(1.95 * float64(physic.MegaHertz)) // 1.95MHz
-	c, err := p.Connect(speed, spi.Mode0, 8)
+	t, err := NewSPITransport(p, dc, rst, cs)
 	if err != nil {
 		return nil, err
 	}
 
-	sh1106 := &SH1106{
-		c:      c,
-		dc:     dc,
-		rst:    rst,
-		cs:     cs,
-		rect:   image.Rect(0, 0, opts.Width, opts.Height),
-		buffer: make([]byte, (opts.Width*opts.Height)/8),
+	return NewSH1106(t, opts)
+}
+
+// NewSH1106I2C creates a new SH1106 display driver for I2C communication
+func NewSH1106I2C(b i2c.Bus, addr uint16, opts *Options) (*SH1106, error) {
+	return NewSH1106(NewI2CTransport(b, addr), opts)
+}
+
+// NewSH1106 creates a new SH1106 display driver on top of an arbitrary transport.
+// It is useful to wrap the transport, e.g. with a Tracer, or to drive an Emulator.
+func NewSH1106(t Transport, opts *Options) (*SH1106, error) {
+	return newDevice(t, ControllerSH1106, opts)
+}
+
+// newDevice creates and initializes a driver for the given controller.
+// All controllers share the buffer, drawing and transport code.
+func newDevice(t Transport, ctrl Controller, opts *Options) (*SH1106, error) {
+	if t == nil {
+		return nil, errors.New("display: transport is required")
+	}
+
+	d := &SH1106{
+		t:          t,
+		ctrl:       ctrl,
+		rect:       image.Rect(0, 0, opts.Width, opts.Height),
+		buffer:     mono.New(image.Rect(0, 0, opts.Width, opts.Height)),
+		activeRows: opts.Height,
+		dither:     opts.Dither,
+		logger:     opts.Logger,
+		metrics:    opts.Metrics,
+	}
+	d.buffer.Polarity = opts.Polarity
+	if d.logger == nil {
+		d.logger = slog.New(slog.DiscardHandler)
+	}
+	if d.metrics == nil {
+		d.metrics = NopMetrics{}
 	}
 
 	// Initialize display
-	if err := sh1106.init(); err != nil {
+	if err := d.init(); err != nil {
+		d.logger.Error("display init failed", "controller", ctrl, "err", err)
 		return nil, err
 	}
+	d.logger.Info("display initialized", "controller", ctrl, "size", d.rect.Max, "transport", fmt.Sprint(t))
 
-	return sh1106, nil
+	return d, nil
 }
 
-// init initializes the SH1106 display with the proper command sequence
+// init initializes the display with the controller command sequence
 func (d *SH1106) init() error {
 	// Hardware reset sequence
-	d.rst.Out(gpio.High)
-	time.Sleep(1 * time.Millisecond)
-	d.rst.Out(gpio.Low)
-	time.Sleep(1 * time.Millisecond)
-	d.rst.Out(gpio.High)
-	time.Sleep(1 * time.Millisecond)
-
-	// Send initialization commands
-	commands := []byte{
-		0xAE, // Turn off OLED panel
-		0x02, // Set low column address
-		0x10, // Set high column address
-		0x40, // Set start line address
-		0x81, // Set contrast control register
-		0xA0, // Set SEG/Column mapping
-		0xC0, // Set COM/Row scan direction
-		0xA6, // Set normal display
-		0xA8, // Set multiplex ratio (1 to 64)
-		0x3F, // 1/64 duty
-		0xD3, // Set display offset
-		0x00, // Not offset
-		0xD5, // Set display clock divide ratio/oscillator frequency
-		0x80, // Set divide ratio, Set Clock as 100 Frames/Sec
-		0xD9, // Set pre-charge period
-		0xF1, // Set Pre-Charge as 15 Clocks & Discharge as 1 Clock
-		0xDA, // Set com pins hardware configuration
-		0x12,
-		0xDB, // Set vcomh
-		0x40, // Set VCOM Deselect Level
-		0x20, // Set Page Addressing Mode
-		0x02,
-		0xA4, // Disable Entire Display On
-		0xA6, // Disable Inverse Display On
-		0xAF, // Turn on OLED panel
-	}
-
-	for _, cmd := range commands {
-		if err := d.sendCommand(cmd); err != nil {
+	if r, ok := d.t.(Resetter); ok {
+		if err := r.Reset(); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	// Send initialization commands
+	return d.sendCommand(d.ctrl.initCommands(d.rect.Dx(), d.rect.Dy())...)
 }
 
-// sendCommand sends a command to the display
-func (d *SH1106) sendCommand(cmd byte) error {
-	if err := d.dc.Out(gpio.Low); err != nil {
-		return err
-	}
-	if err := d.cs.Out(gpio.Low); err != nil {
-		return err
-	}
-	defer d.cs.Out(gpio.High)
-
-	return d.c.Tx([]byte{cmd}, nil)
+// sendCommand sends one or more commands to the display
+func (d *SH1106) sendCommand(cmds ...byte) error {
+	return d.track(len(cmds), d.t.Command(cmds...))
 }
 
 // sendData sends data to the display
 func (d *SH1106) sendData(data []byte) error {
-	if err := d.dc.Out(gpio.High); err != nil {
+	return d.track(len(data), d.t.Data(data))
+}
+
+// track accounts for a transport write of n bytes
+func (d *SH1106) track(n int, err error) error {
+	if err != nil {
+		d.metrics.TransactionError(err)
 		return err
 	}
-	if err := d.cs.Out(gpio.Low); err != nil {
+	d.sent += n
+	return nil
+}
+
+// display sends the buffer to the display and reports the flush
+func (d *SH1106) display() error {
+	start := time.Now()
+	d.sent = 0
+
+	if err := d.flush(); err != nil {
+		d.logger.Error("display flush failed", "controller", d.ctrl, "err", err)
 		return err
 	}
-	defer d.cs.Out(gpio.High)
 
-	return d.c.Tx(data, nil)
+	latency := time.Since(start)
+	if !d.lastFlush.IsZero() {
+		// Exponential moving average of the instantaneous rate
+		if dt := start.Sub(d.lastFlush).Seconds(); dt > 0 {
+			if d.fps == 0 {
+				d.fps = 1 / dt
+			} else {
+				d.fps = 0.9*d.fps + 0.1/dt
+			}
+		}
+	}
+	d.lastFlush = start
+
+	d.metrics.FrameFlushed(d.sent, latency)
+	d.metrics.FPS(d.fps)
+	d.logger.Debug("display flushed", "bytes", d.sent, "latency", latency)
+
+	return nil
 }
 
-// display sends the buffer to the display
-func (d *SH1106) display() error {
+// flush writes the buffer to the controller RAM.
+// Only the pages covering the active area are sent.
+func (d *SH1106) flush() error {
 	w := d.rect.Dx()
-	h := d.rect.Dy()
-	pages := h / 8
+	pages := (d.activeRows + 7) / 8
+
+	if d.ctrl.horizontal() {
+		// Set column and page ranges, then stream the whole frame
+		if err := d.sendCommand(0x21, 0x00, byte(w-1), 0x22, 0x00, byte(pages-1)); err != nil {
+			return err
+		}
+		return d.sendData(d.buffer.Pix[:w*pages])
+	}
 
+	col := d.ctrl.columnOffset()
 	for page := range pages {
 		// Set page address
 		if err := d.sendCommand(0xB0 + byte(page)); err != nil {
 			return err
 		}
 		// Set low column address
-		if err := d.sendCommand(0x02); err != nil {
+		if err := d.sendCommand(byte(col & 0x0F)); err != nil {
 			return err
 		}
 		// Set high column address
-		if err := d.sendCommand(0x10); err != nil {
+		if err := d.sendCommand(0x10 | byte(col>>4)); err != nil {
 			return err
 		}
 
 		// Send page data
 		start := page * w
-		pageData := make([]byte, w)
-		for i := range w {
-			pageData[i] = ^d.buffer[start+i] // Invert for SH1106
-		}
-		if err := d.sendData(pageData); err != nil {
+		if err := d.sendData(d.buffer.Pix[start : start+w]); err != nil {
 			return err
 		}
 	}
@@ -171,33 +218,24 @@ func (d *SH1106) display() error {
 	return nil
 }
 
-// setPixel sets a pixel in the buffer
+// setPixel lights or darkens a pixel in the buffer
 func (d *SH1106) setPixel(x, y int, on bool) {
-	if x < 0 || x >= d.rect.Dx() || y < 0 || y >= d.rect.Dy() {
-		return
-	}
-
-	page := y / 8
-	bit := y % 8
-	index := page*d.rect.Dx() + x
-
-	if on {
-		d.buffer[index] |= (1 << bit)
-	} else {
-		d.buffer[index] &^= (1 << bit)
-	}
+	d.buffer.SetPixel(x, y, on)
 }
 
-// Clear clears the display buffer
+// Clear darkens every pixel of the display buffer
 func (d *SH1106) Clear() {
-	for i := range d.buffer {
-		d.buffer[i] = 0xFF
-	}
+	d.buffer.Fill(false)
 }
 
 // String implements fmt.Stringer
 func (d *SH1106) String() string {
-	return fmt.Sprintf("SH1106{%s, %s}", d.rect.Max, d.c)
+	return fmt.Sprintf("%s{%s, %s}", d.ctrl, d.rect.Max, d.t)
+}
+
+// Controller returns the controller chip this driver talks to
+func (d *SH1106) Controller() Controller {
+	return d.ctrl
 }
 
 // Bounds returns the display bounds
@@ -210,6 +248,59 @@ func (d *SH1106) ColorModel() color.Model {
 	return color.GrayModel
 }
 
+// SetActiveArea lowers the multiplex ratio so that only the top rows of the
+// panel are driven, saving power when e.g. a single status line is shown.
+// Logical coordinates do not change: pixels below the area stay in the buffer
+// but are neither flushed nor shown. A value of 0 or the display height
+// restores the full panel, flushing the buffer first so no stale rows appear.
+func (d *SH1106) SetActiveArea(rows int) error {
+	h := d.rect.Dy()
+	if rows <= 0 || rows > h {
+		rows = h
+	}
+	if rows < d.ctrl.minMultiplex() {
+		return fmt.Errorf("display: %s needs at least %d active rows, got %d", d.ctrl, d.ctrl.minMultiplex(), rows)
+	}
+
+	grow := rows > d.activeRows
+	d.activeRows = rows
+	if grow {
+		if err := d.display(); err != nil {
+			return err
+		}
+	}
+
+	return d.sendCommand(
+		0xA8, byte(rows-1), // Set multiplex ratio
+		0xD3, byte(d.ctrl.displayOffset(h, rows)), // Keep RAM row 0 on the top row
+	)
+}
+
+// ActiveArea returns the number of rows currently driven
+func (d *SH1106) ActiveArea() int {
+	return d.activeRows
+}
+
+// FPS returns the current flush rate, averaged over the last frames
+func (d *SH1106) FPS() float64 {
+	return d.fps
+}
+
+// SetDither changes how Draw turns gray levels into pixels
+func (d *SH1106) SetDither(dither mono.Dither) {
+	d.dither = dither
+}
+
+// At returns the color of a buffer pixel according to the display polarity
+func (d *SH1106) At(x, y int) color.Color {
+	return d.buffer.At(x, y)
+}
+
+// Set lights a buffer pixel if c maps to a lit pixel under the display polarity
+func (d *SH1106) Set(x, y int, c color.Color) {
+	d.buffer.Set(x, y, c)
+}
+
 // Halt turns off the display
 func (d *SH1106) Halt() error {
 	return d.sendCommand(0xAE)
@@ -219,20 +310,24 @@ func (d *SH1106) Halt() error {
 func (d *SH1106) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
 	bounds := src.Bounds()
 
+	// Dithering needs the whole source to spread the error
+	var dithered *mono.Image
+	if d.dither != mono.Threshold {
+		dithered = mono.Convert(src, d.buffer.Polarity, d.dither)
+	}
+
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
 			srcX := x - bounds.Min.X + sp.X
 			srcY := y - bounds.Min.Y + sp.Y
 
 			if srcX >= 0 && srcX < d.rect.Dx() && srcY >= 0 && srcY < d.rect.Dy() {
-				// Convert to grayscale
-				r, g, b, _ := src.At(x, y).RGBA()
-				gray := uint8((r + g + b) / 3 >> 8)
-
-				// Set pixel based on threshold
-				// Pixels darker than 50% are on
-				on := gray < 128
-				d.setPixel(srcX, srcY, on)
+				if dithered != nil {
+					d.setPixel(srcX, srcY, dithered.GetPixel(x, y))
+				} else {
+					// Threshold at 50% gray, the polarity tells which side is lit
+					d.setPixel(srcX, srcY, d.buffer.Polarity.Lit(src.At(x, y)))
+				}
 			}
 		}
 	}
@@ -240,7 +335,7 @@ func (d *SH1106) Draw(r image.Rectangle, src image.Image, sp image.Point) error
 	return d.display()
 }
 
-// SetPixel directly sets a pixel on the display
+// SetPixel directly lights or darkens a pixel on the display
 func (d *SH1106) SetPixel(x, y int, on bool) {
 	d.setPixel(x, y, on)
 }
@@ -249,3 +344,49 @@ func (d *SH1106) SetPixel(x, y int, on bool) {
 func (d *SH1106) Update() error {
 	return d.display()
 }
+
+// GetPixel reports whether a pixel is lit in the buffer
+func (d *SH1106) GetPixel(x, y int) bool {
+	return d.buffer.GetPixel(x, y)
+}
+
+// Fill lights or darkens every pixel in the buffer
+func (d *SH1106) Fill(on bool) {
+	d.buffer.Fill(on)
+}
+
+// Invert flips every pixel of the buffer inside r
+func (d *SH1106) Invert(r image.Rectangle) {
+	d.buffer.Invert(r)
+}
+
+// Scroll shifts the buffer content by dx, dy. Pixels shifted in are dark.
+func (d *SH1106) Scroll(dx, dy int) {
+	d.buffer.Scroll(dx, dy)
+}
+
+// CopyRect copies the buffer pixels inside r so that r.Min lands on dp
+func (d *SH1106) CopyRect(r image.Rectangle, dp image.Point) {
+	d.buffer.CopyRect(r, dp)
+}
+
+// Buffer returns the live page-packed buffer, e.g. for fast blitting.
+// Changes are shown on the next Update.
+func (d *SH1106) Buffer() *mono.Image {
+	return d.buffer
+}
+
+// SaveBuffer returns a snapshot of the buffer, e.g. before drawing a popup.
+// The snapshot carries the display polarity, so it can be encoded as a preview.
+func (d *SH1106) SaveBuffer() *mono.Image {
+	return d.buffer.Clone()
+}
+
+// RestoreBuffer replaces the buffer with a snapshot taken by SaveBuffer.
+// The screen is not refreshed until the next Update.
+func (d *SH1106) RestoreBuffer(snapshot *mono.Image) error {
+	if snapshot == nil || !d.buffer.CopyFrom(snapshot) {
+		return errors.New("display: snapshot does not match the display size")
+	}
+	return nil
+}
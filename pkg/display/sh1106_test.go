@@ -2,7 +2,10 @@ package display
 
 import (
 	"image"
+	"image/color"
 	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
 )
 
 func TestSH1106Options(t *testing.T) {
@@ -44,3 +47,169 @@ func TestSH1106String(t *testing.T) {
 		t.Error("String() should not return empty string")
 	}
 }
+
+func TestSH1106BufferSnapshots(t *testing.T) {
+	dev, _ := newEmulatedSH1106(t)
+
+	dev.Fill(false)
+	dev.SetPixel(1, 2, true)
+	snap := dev.SaveBuffer()
+
+	dev.Invert(dev.Bounds())
+	if dev.GetPixel(1, 2) {
+		t.Error("Expected pixel (1, 2) to be unset after Invert")
+	}
+
+	if err := dev.RestoreBuffer(snap); err != nil {
+		t.Fatalf("RestoreBuffer failed: %v", err)
+	}
+	if !dev.GetPixel(1, 2) || dev.GetPixel(0, 0) {
+		t.Error("Expected buffer to match the snapshot")
+	}
+
+	if err := dev.RestoreBuffer(nil); err == nil {
+		t.Error("Expected error restoring a nil snapshot, got nil")
+	}
+}
+
+func TestSH1106ScrollAndCopyRect(t *testing.T) {
+	dev, _ := newEmulatedSH1106(t)
+
+	dev.Fill(false)
+	dev.SetPixel(0, 0, true)
+	dev.Scroll(0, 8)
+	if !dev.GetPixel(0, 8) {
+		t.Error("Expected pixel to scroll to (0, 8)")
+	}
+
+	dev.CopyRect(image.Rect(0, 8, 1, 9), image.Pt(127, 63))
+	if !dev.GetPixel(127, 63) {
+		t.Error("Expected pixel to be copied to (127, 63)")
+	}
+}
+
+func TestSH1106DrawPolarity(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 128, 64))
+	src.Pix[0] = 0xFF // Only (0, 0) is white
+
+	for _, polarity := range []mono.Polarity{mono.LitIsWhite, mono.LitIsBlack} {
+		opts := &Options{Width: 128, Height: 64, Polarity: polarity}
+		emu := NewSH1106Emulator(opts)
+		dev, err := NewSH1106(emu, opts)
+		if err != nil {
+			t.Fatalf("Failed to create SH1106: %v", err)
+		}
+
+		if err := dev.Draw(src.Bounds(), src, image.Point{}); err != nil {
+			t.Fatalf("Draw failed: %v", err)
+		}
+
+		whiteLit := polarity == mono.LitIsWhite
+		if emu.Lit(0, 0) != whiteLit || emu.Lit(1, 0) == whiteLit {
+			t.Errorf("%s: panel does not match the polarity", polarity)
+		}
+
+		// Snapshots render lit pixels with the polarity colors
+		snap := dev.SaveBuffer()
+		if snap.At(0, 0) != color.White || snap.At(1, 0) != color.Black {
+			t.Errorf("%s: snapshot does not match the source image", polarity)
+		}
+	}
+}
+
+func TestSH1106DrawDither(t *testing.T) {
+	// 50% gray is all dark when thresholded and half lit when dithered
+	src := image.NewUniform(color.Gray{Y: 0x7F})
+	r := image.Rect(0, 0, 128, 64)
+
+	opts := &Options{Width: 128, Height: 64}
+	dev, err := NewSH1106(NewSH1106Emulator(opts), opts)
+	if err != nil {
+		t.Fatalf("Failed to create SH1106: %v", err)
+	}
+
+	for _, tc := range []struct {
+		dither mono.Dither
+		lit    int
+	}{
+		{mono.Threshold, 0},
+		{mono.Bayer, 128 * 64 / 2},
+	} {
+		dev.SetDither(tc.dither)
+		if err := dev.Draw(r, &bounded{src, r}, image.Point{}); err != nil {
+			t.Fatalf("Draw failed: %v", err)
+		}
+
+		lit := 0
+		for y := 0; y < 64; y++ {
+			for x := 0; x < 128; x++ {
+				if dev.GetPixel(x, y) {
+					lit++
+				}
+			}
+		}
+		if lit != tc.lit {
+			t.Errorf("%s: Expected %d lit pixels, got %d", tc.dither, tc.lit, lit)
+		}
+	}
+}
+
+// bounded gives an infinite image finite bounds
+type bounded struct {
+	image.Image
+	r image.Rectangle
+}
+
+func (b *bounded) Bounds() image.Rectangle {
+	return b.r
+}
+
+func TestSH1106SetActiveArea(t *testing.T) {
+	dev, emu := newEmulatedSH1106(t)
+
+	dev.Clear()
+	dev.SetPixel(0, 3, true)
+	dev.SetPixel(0, 40, true)
+	if err := dev.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if err := dev.SetActiveArea(8); err != nil {
+		t.Fatalf("SetActiveArea failed: %v", err)
+	}
+	if dev.ActiveArea() != 8 || emu.mux != 7 {
+		t.Errorf("Expected 8 active rows, got %d (mux %d)", dev.ActiveArea(), emu.mux)
+	}
+	if !emu.Lit(0, 3) || emu.Lit(0, 40) {
+		t.Error("Expected only the top rows to be shown, at their logical position")
+	}
+
+	// Changes below the area are kept in the buffer and shown on restore
+	dev.SetPixel(0, 40, false)
+	dev.SetPixel(0, 50, true)
+	if err := dev.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if err := dev.SetActiveArea(0); err != nil {
+		t.Fatalf("SetActiveArea failed: %v", err)
+	}
+	if dev.ActiveArea() != 64 || emu.mux != 63 {
+		t.Errorf("Expected full panel, got %d rows (mux %d)", dev.ActiveArea(), emu.mux)
+	}
+	if !emu.Lit(0, 3) || emu.Lit(0, 40) || !emu.Lit(0, 50) {
+		t.Error("Expected the full buffer to be shown after restoring")
+	}
+}
+
+func TestSSD1306SetActiveAreaMinimum(t *testing.T) {
+	opts := &Options{Width: 128, Height: 64}
+	dev, err := NewSSD1306(NewSSD1306Emulator(opts), opts)
+	if err != nil {
+		t.Fatalf("Failed to create SSD1306: %v", err)
+	}
+
+	if err := dev.SetActiveArea(8); err == nil {
+		t.Error("Expected error for fewer than 16 rows on SSD1306, got nil")
+	}
+}
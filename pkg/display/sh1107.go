@@ -0,0 +1,42 @@
+package display
+
+import (
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/spi"
+)
+
+var _ display.Drawer = (*SH1107)(nil)
+
+// SH1107 driver for 128x128 OLED displays. It reuses the SH1106 buffer,
+// Draw and page-by-page flush; the controller differs in its 16 page RAM,
+// the two byte start line command and the COM offset needed by 64 row modules.
+type SH1107 struct {
+	*SH1106
+}
+
+// NewSH1107SPI creates a new SH1107 display driver for SPI communication
+func NewSH1107SPI(p spi.Port, dc, rst, cs gpio.PinOut, opts *Options) (*SH1107, error) {
+	t, err := NewSPITransport(p, dc, rst, cs)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSH1107(t, opts)
+}
+
+// NewSH1107I2C creates a new SH1107 display driver for I2C communication
+func NewSH1107I2C(b i2c.Bus, addr uint16, opts *Options) (*SH1107, error) {
+	return NewSH1107(NewI2CTransport(b, addr), opts)
+}
+
+// NewSH1107 creates a new SH1107 display driver on top of an arbitrary transport
+func NewSH1107(t Transport, opts *Options) (*SH1107, error) {
+	d, err := newDevice(t, ControllerSH1107, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SH1107{SH1106: d}, nil
+}
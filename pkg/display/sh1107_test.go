@@ -0,0 +1,149 @@
+package display
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"periph.io/x/conn/v3/i2c/i2ctest"
+)
+
+func newEmulatedSH1107(t *testing.T) (*SH1107, *Emulator) {
+	return newEmulatedSH1107Size(t, 128, 128)
+}
+
+func newEmulatedSH1107Size(t *testing.T, width, height int) (*SH1107, *Emulator) {
+	t.Helper()
+
+	opts := &Options{Width: width, Height: height}
+	emu := NewSH1107Emulator(opts)
+	dev, err := NewSH1107(emu, opts)
+	if err != nil {
+		t.Fatalf("Failed to create SH1107 on emulator: %v", err)
+	}
+
+	return dev, emu
+}
+
+func TestSH1107Init(t *testing.T) {
+	dev, emu := newEmulatedSH1107(t)
+
+	if dev.Controller() != ControllerSH1107 {
+		t.Errorf("Expected controller SH1107, got %s", dev.Controller())
+	}
+
+	if emu.mux != 127 {
+		t.Errorf("Expected multiplex ratio 127, got %d", emu.mux)
+	}
+
+	if emu.offset != 0 {
+		t.Errorf("Expected display offset 0 for 128 rows, got %d", emu.offset)
+	}
+
+	if len(emu.pending) != 0 {
+		t.Errorf("Expected no pending command bytes, got %v", emu.pending)
+	}
+}
+
+func TestSH1107Update(t *testing.T) {
+	dev, emu := newEmulatedSH1107(t)
+
+	dev.Clear()
+	for _, p := range []image.Point{{0, 0}, {127, 0}, {64, 100}, {127, 127}} {
+		dev.SetPixel(p.X, p.Y, true)
+	}
+	if err := dev.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	for _, p := range []image.Point{{0, 0}, {127, 0}, {64, 100}, {127, 127}} {
+		if !emu.Lit(p.X, p.Y) {
+			t.Errorf("Expected pixel %v to be lit", p)
+		}
+	}
+
+	if emu.Lit(1, 0) || emu.Lit(64, 99) {
+		t.Error("Expected neighbouring pixels to be dark")
+	}
+}
+
+func TestSH1107Draw(t *testing.T) {
+	dev, emu := newEmulatedSH1107(t)
+
+	src := image.NewGray(image.Rect(0, 0, 128, 128))
+	src.SetGray(10, 120, color.Gray{Y: 0xFF})
+	if err := dev.Draw(src.Bounds(), src, image.Point{}); err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+
+	if !emu.Lit(10, 120) || emu.Lit(10, 119) {
+		t.Error("Panel does not match the drawn image")
+	}
+}
+
+func TestSH1107DisplayOffset(t *testing.T) {
+	if got := ControllerSH1107.displayOffset(64, 64); got != 0x60 {
+		t.Errorf("Expected display offset 0x60 for 64 rows, got 0x%02X", got)
+	}
+
+	if got := ControllerSH1106.displayOffset(64, 64); got != 0 {
+		t.Errorf("Expected display offset 0 for SH1106, got 0x%02X", got)
+	}
+}
+
+func TestSH1107Emulated64Rows(t *testing.T) {
+	dev, emu := newEmulatedSH1107Size(t, 128, 64)
+
+	if emu.offset != 0x60 {
+		t.Errorf("Expected display offset 0x60 for 64 rows, got 0x%02X", emu.offset)
+	}
+
+	points := []image.Point{{0, 0}, {127, 0}, {64, 32}, {0, 63}, {127, 63}}
+	dev.Clear()
+	for _, p := range points {
+		dev.SetPixel(p.X, p.Y, true)
+	}
+	if err := dev.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	for _, p := range points {
+		if !emu.Lit(p.X, p.Y) {
+			t.Errorf("Expected pixel %v to be lit", p)
+		}
+	}
+	if emu.Lit(1, 0) || emu.Lit(64, 31) || emu.Lit(0, 62) {
+		t.Error("Expected neighbouring pixels to be dark")
+	}
+
+	// A reduced active area keeps the top rows
+	if err := dev.SetActiveArea(16); err != nil {
+		t.Fatalf("SetActiveArea failed: %v", err)
+	}
+	if !emu.Lit(0, 0) || !emu.Lit(127, 0) {
+		t.Error("Expected the top row to stay lit")
+	}
+	if emu.Lit(64, 32) || emu.Lit(0, 63) {
+		t.Error("Expected rows below the active area to be dark")
+	}
+}
+
+func TestNewSH1107I2C(t *testing.T) {
+	opts := &Options{Width: 128, Height: 64}
+	bus := &i2ctest.Playback{
+		DontPanic: true,
+		Ops:       []i2ctest.IO{initOp(I2CAddrAlt, ControllerSH1107, opts)},
+	}
+
+	dev, err := NewSH1107I2C(bus, I2CAddrAlt, opts)
+	if err != nil {
+		t.Fatalf("NewSH1107I2C failed: %v", err)
+	}
+	if dev.Controller() != ControllerSH1107 {
+		t.Errorf("Expected controller SH1107, got %s", dev.Controller())
+	}
+
+	if err := bus.Close(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,42 @@
+package display
+
+import (
+	"periph.io/x/conn/v3/display"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/spi"
+)
+
+var _ display.Drawer = (*SSD1306)(nil)
+
+// SSD1306 driver for OLED displays. It shares the buffer, drawing and
+// transport code with SH1106; only the controller command set differs:
+// no column offset, horizontal addressing and the internal charge pump.
+type SSD1306 struct {
+	*SH1106
+}
+
+// NewSSD1306SPI creates a new SSD1306 display driver for SPI communication
+func NewSSD1306SPI(p spi.Port, dc, rst, cs gpio.PinOut, opts *Options) (*SSD1306, error) {
+	t, err := NewSPITransport(p, dc, rst, cs)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSSD1306(t, opts)
+}
+
+// NewSSD1306I2C creates a new SSD1306 display driver for I2C communication
+func NewSSD1306I2C(b i2c.Bus, addr uint16, opts *Options) (*SSD1306, error) {
+	return NewSSD1306(NewI2CTransport(b, addr), opts)
+}
+
+// NewSSD1306 creates a new SSD1306 display driver on top of an arbitrary transport
+func NewSSD1306(t Transport, opts *Options) (*SSD1306, error) {
+	d, err := newDevice(t, ControllerSSD1306, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SSD1306{SH1106: d}, nil
+}
@@ -0,0 +1,103 @@
+package display
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSSD1306Update(t *testing.T) {
+	opts := &Options{Width: 128, Height: 64}
+	emu := NewSSD1306Emulator(opts)
+	dev, err := NewSSD1306(emu, opts)
+	if err != nil {
+		t.Fatalf("Failed to create SSD1306 on emulator: %v", err)
+	}
+
+	if dev.Controller() != ControllerSSD1306 {
+		t.Errorf("Expected controller SSD1306, got %s", dev.Controller())
+	}
+
+	dev.Clear()
+	dev.SetPixel(0, 0, true)
+	dev.SetPixel(64, 33, true)
+	dev.SetPixel(127, 63, true)
+	if err := dev.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if !bytes.Equal(emu.ram, dev.buffer.Pix) {
+		t.Error("Expected RAM to match the buffer without column offset")
+	}
+
+	if !emu.Lit(0, 0) || !emu.Lit(64, 33) || !emu.Lit(127, 63) || emu.Lit(1, 0) {
+		t.Error("Panel does not match the buffer")
+	}
+}
+
+func TestSSD1306ChargePump(t *testing.T) {
+	var buf bytes.Buffer
+	opts := &Options{Width: 128, Height: 32}
+	if _, err := NewSSD1306(NewTracer(nil, &buf), opts); err != nil {
+		t.Fatalf("Failed to create SSD1306: %v", err)
+	}
+
+	var cmds []byte
+	tr := NewTraceReader(&buf)
+	for {
+		rec, err := tr.Next()
+		if err != nil {
+			break
+		}
+		if rec.Kind == TraceCommand {
+			cmds = append(cmds, rec.Bytes...)
+		}
+	}
+
+	if !bytes.Contains(cmds, []byte{0x8D, 0x14}) {
+		t.Error("Expected charge pump to be enabled")
+	}
+
+	if !bytes.Contains(cmds, []byte{0xA8, 31}) {
+		t.Error("Expected multiplex ratio of 32 rows")
+	}
+}
+
+func TestSSD1306SetActiveArea(t *testing.T) {
+	for _, height := range []int{64, 32} {
+		opts := &Options{Width: 128, Height: height}
+		emu := NewSSD1306Emulator(opts)
+		dev, err := NewSSD1306(emu, opts)
+		if err != nil {
+			t.Fatalf("Failed to create SSD1306 on emulator: %v", err)
+		}
+
+		dev.Clear()
+		dev.SetPixel(0, 0, true)
+		dev.SetPixel(5, 15, true)
+		dev.SetPixel(0, height-1, true)
+		if err := dev.Update(); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+
+		if err := dev.SetActiveArea(16); err != nil {
+			t.Fatalf("SetActiveArea failed: %v", err)
+		}
+		if !emu.Lit(0, 0) || !emu.Lit(5, 15) {
+			t.Errorf("%d rows: expected the area on the top rows, at their logical position", height)
+		}
+		for y := 16; y < height; y++ {
+			for x := 0; x < 128; x++ {
+				if emu.Lit(x, y) {
+					t.Fatalf("%d rows: expected row %d below the area to be dark", height, y)
+				}
+			}
+		}
+
+		if err := dev.SetActiveArea(0); err != nil {
+			t.Fatalf("SetActiveArea failed: %v", err)
+		}
+		if !emu.Lit(0, 0) || !emu.Lit(5, 15) || !emu.Lit(0, height-1) {
+			t.Errorf("%d rows: expected the full panel after restoring", height)
+		}
+	}
+}
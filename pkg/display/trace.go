@@ -0,0 +1,255 @@
+package display
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Trace file layout:
+//
+//	header: "SHTR" magic followed by a one byte version
+//	record: kind (1 byte), time since previous record in microseconds
+//	        (uvarint), payload length (uvarint), payload bytes
+const (
+	traceMagic   = "SHTR"
+	traceVersion = 1
+
+	// maxTraceRecord bounds the payload of a record, far above the largest
+	// frame write, so a corrupt length cannot exhaust memory
+	maxTraceRecord = 1 << 20
+)
+
+// TraceKind identifies the kind of a trace record
+type TraceKind byte
+
+const (
+	// TraceCommand is a write with D/C low
+	TraceCommand TraceKind = iota
+	// TraceData is a display RAM write with D/C high
+	TraceData
+	// TraceReset is a hardware reset, it carries no payload
+	TraceReset
+)
+
+// String implements fmt.Stringer
+func (k TraceKind) String() string {
+	switch k {
+	case TraceCommand:
+		return "command"
+	case TraceData:
+		return "data"
+	case TraceReset:
+		return "reset"
+	}
+	return fmt.Sprintf("TraceKind(%d)", byte(k))
+}
+
+// TraceRecord is a single transport write captured by a Tracer
+type TraceRecord struct {
+	Kind TraceKind
+	// Delay is the time elapsed since the previous record
+	Delay time.Duration
+	Bytes []byte
+}
+
+// Tracer is a Transport that records every write into a trace before
+// forwarding it to the wrapped transport
+type Tracer struct {
+	t Transport
+	w io.Writer
+
+	mu     sync.Mutex
+	last   time.Time
+	header bool
+	buf    []byte
+}
+
+var (
+	_ Transport = (*Tracer)(nil)
+	_ Resetter  = (*Tracer)(nil)
+)
+
+// NewTracer wraps t and writes the trace to w. t may be nil to record a
+// session without any panel attached.
+func NewTracer(t Transport, w io.Writer) *Tracer {
+	return &Tracer{t: t, w: w}
+}
+
+// Command records and forwards command bytes
+func (t *Tracer) Command(cmds ...byte) error {
+	if err := t.record(TraceCommand, cmds); err != nil {
+		return err
+	}
+	if t.t == nil {
+		return nil
+	}
+	return t.t.Command(cmds...)
+}
+
+// Data records and forwards data bytes
+func (t *Tracer) Data(data []byte) error {
+	if err := t.record(TraceData, data); err != nil {
+		return err
+	}
+	if t.t == nil {
+		return nil
+	}
+	return t.t.Data(data)
+}
+
+// Reset records the reset and forwards it if the wrapped transport supports it
+func (t *Tracer) Reset() error {
+	if err := t.record(TraceReset, nil); err != nil {
+		return err
+	}
+	if r, ok := t.t.(Resetter); ok {
+		return r.Reset()
+	}
+	return nil
+}
+
+// String implements fmt.Stringer
+func (t *Tracer) String() string {
+	return fmt.Sprintf("Tracer{%v}", t.t)
+}
+
+// record encodes a single record and writes it in one call
+func (t *Tracer) record(kind TraceKind, b []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var delay time.Duration
+	if !t.last.IsZero() {
+		delay = now.Sub(t.last)
+	}
+	t.last = now
+
+	t.buf = t.buf[:0]
+	if !t.header {
+		t.buf = append(t.buf, traceMagic...)
+		t.buf = append(t.buf, traceVersion)
+		t.header = true
+	}
+	t.buf = append(t.buf, byte(kind))
+	t.buf = binary.AppendUvarint(t.buf, uint64(delay/time.Microsecond))
+	t.buf = binary.AppendUvarint(t.buf, uint64(len(b)))
+	t.buf = append(t.buf, b...)
+
+	_, err := t.w.Write(t.buf)
+	return err
+}
+
+// TraceReader decodes a trace written by a Tracer
+type TraceReader struct {
+	r      *bufio.Reader
+	header bool
+}
+
+// NewTraceReader returns a reader decoding the trace in r
+func NewTraceReader(r io.Reader) *TraceReader {
+	return &TraceReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next record, or io.EOF at the end of the trace
+func (tr *TraceReader) Next() (TraceRecord, error) {
+	if !tr.header {
+		var hdr [len(traceMagic) + 1]byte
+		if _, err := io.ReadFull(tr.r, hdr[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return TraceRecord{}, errors.New("display: truncated trace header")
+			}
+			return TraceRecord{}, err
+		}
+		if string(hdr[:len(traceMagic)]) != traceMagic {
+			return TraceRecord{}, errors.New("display: not a trace file")
+		}
+		if hdr[len(traceMagic)] != traceVersion {
+			return TraceRecord{}, fmt.Errorf("display: unsupported trace version %d", hdr[len(traceMagic)])
+		}
+		tr.header = true
+	}
+
+	kind, err := tr.r.ReadByte()
+	if err != nil {
+		return TraceRecord{}, err
+	}
+	if TraceKind(kind) > TraceReset {
+		return TraceRecord{}, fmt.Errorf("display: unknown trace record kind %d", kind)
+	}
+
+	delay, err := binary.ReadUvarint(tr.r)
+	if err != nil {
+		return TraceRecord{}, truncated(err)
+	}
+	n, err := binary.ReadUvarint(tr.r)
+	if err != nil {
+		return TraceRecord{}, truncated(err)
+	}
+
+	rec := TraceRecord{
+		Kind:  TraceKind(kind),
+		Delay: time.Duration(delay) * time.Microsecond,
+	}
+	if n > maxTraceRecord {
+		return TraceRecord{}, errors.New("display: invalid trace record")
+	}
+	if n > 0 {
+		// Grow the payload as bytes arrive rather than trusting the length
+		var buf bytes.Buffer
+		if _, err := io.CopyN(&buf, tr.r, int64(n)); err != nil {
+			return TraceRecord{}, truncated(err)
+		}
+		rec.Bytes = buf.Bytes()
+	}
+
+	return rec, nil
+}
+
+// truncated converts an EOF in the middle of a record into an error
+func truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("display: truncated trace record")
+	}
+	return err
+}
+
+// Replay feeds every record of the trace in r to t. When speed is greater
+// than zero the recorded delays are honored, scaled by 1/speed; otherwise the
+// trace is replayed as fast as possible.
+func Replay(r io.Reader, t Transport, speed float64) error {
+	tr := NewTraceReader(r)
+	for {
+		rec, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if speed > 0 && rec.Delay > 0 {
+			time.Sleep(time.Duration(float64(rec.Delay) / speed))
+		}
+
+		switch rec.Kind {
+		case TraceCommand:
+			err = t.Command(rec.Bytes...)
+		case TraceData:
+			err = t.Data(rec.Bytes)
+		case TraceReset:
+			if rs, ok := t.(Resetter); ok {
+				err = rs.Reset()
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package display
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func TestTraceRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := NewTracer(nil, &buf)
+
+	tracer.Reset()
+	tracer.Command(0xB0, 0x02, 0x10)
+	tracer.Data([]byte{0x01, 0x02, 0x03})
+
+	tr := NewTraceReader(&buf)
+	expected := []TraceRecord{
+		{Kind: TraceReset},
+		{Kind: TraceCommand, Bytes: []byte{0xB0, 0x02, 0x10}},
+		{Kind: TraceData, Bytes: []byte{0x01, 0x02, 0x03}},
+	}
+
+	for i, want := range expected {
+		rec, err := tr.Next()
+		if err != nil {
+			t.Fatalf("Record %d: unexpected error %v", i, err)
+		}
+		if rec.Kind != want.Kind {
+			t.Errorf("Record %d: expected kind %s, got %s", i, want.Kind, rec.Kind)
+		}
+		if !bytes.Equal(rec.Bytes, want.Bytes) {
+			t.Errorf("Record %d: expected bytes %v, got %v", i, want.Bytes, rec.Bytes)
+		}
+	}
+
+	if _, err := tr.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF at end of trace, got %v", err)
+	}
+}
+
+func TestTraceInvalid(t *testing.T) {
+	if _, err := NewTraceReader(bytes.NewReader([]byte("NOPE\x01"))).Next(); err == nil {
+		t.Error("Expected error for bad magic, got nil")
+	}
+
+	if _, err := NewTraceReader(bytes.NewReader([]byte("SHTR\x01\x01\x00\x05\x01"))).Next(); err == nil {
+		t.Error("Expected error for truncated record, got nil")
+	}
+
+	// A length far beyond any record, then one just above the limit
+	for _, length := range [][]byte{
+		{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01},
+		binary.AppendUvarint(nil, maxTraceRecord+1),
+	} {
+		trace := append([]byte("SHTR\x01\x01\x00"), length...)
+		_, err := NewTraceReader(bytes.NewReader(trace)).Next()
+		if err == nil || err.Error() != "display: invalid trace record" {
+			t.Errorf("Expected an invalid trace record error, got %v", err)
+		}
+	}
+
+	// A large but valid length without the payload is truncated
+	trace := append([]byte("SHTR\x01\x01\x00"), binary.AppendUvarint(nil, maxTraceRecord)...)
+	if _, err := NewTraceReader(bytes.NewReader(trace)).Next(); err == nil {
+		t.Error("Expected error for truncated record, got nil")
+	}
+}
+
+func TestReplayIntoEmulator(t *testing.T) {
+	opts := &Options{Width: 128, Height: 64}
+
+	var buf bytes.Buffer
+	live := NewSH1106Emulator(opts)
+	dev, err := NewSH1106(NewTracer(live, &buf), opts)
+	if err != nil {
+		t.Fatalf("Failed to create SH1106: %v", err)
+	}
+
+	dev.Clear()
+	dev.SetPixel(5, 5, true)
+	dev.SetPixel(100, 40, true)
+	if err := dev.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	replayed := NewSH1106Emulator(opts)
+	if err := Replay(&buf, replayed, 0); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+
+	if !bytes.Equal(live.Image().Pix, replayed.Image().Pix) {
+		t.Error("Replayed panel differs from the recorded one")
+	}
+
+	if !replayed.Lit(5, 5) || !replayed.Lit(100, 40) {
+		t.Error("Expected recorded pixels to be lit after replay")
+	}
+}
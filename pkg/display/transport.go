@@ -0,0 +1,144 @@
+package display
+
+import (
+	"errors"
+	"time"
+
+	"periph.io/x/conn/v3"
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/i2c"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+)
+
+// Transport carries command and data bytes to a display controller
+type Transport interface {
+	// Command sends one or more command bytes
+	Command(cmds ...byte) error
+	// Data sends display RAM data
+	Data(data []byte) error
+}
+
+// Resetter is implemented by transports that can hardware-reset the controller
+type Resetter interface {
+	Reset() error
+}
+
+// SPITransport drives a display over 4-wire SPI using a D/C pin
+type SPITransport struct {
+	c   conn.Conn
+	dc  gpio.PinOut
+	rst gpio.PinOut
+	cs  gpio.PinOut
+}
+
+var _ Resetter = (*SPITransport)(nil)
+
+// NewSPITransport connects to the SPI port and returns a transport for it
+func NewSPITransport(p spi.Port, dc, rst, cs gpio.PinOut) (*SPITransport, error) {
+	if dc == nil || rst == nil || cs == nil {
+		return nil, errors.New("display: dc, rst, and cs pins are required")
+	}
+
+	speed := physic.Frequency(1.95 * float64(physic.MegaHertz)) // 1.95MHz
+	c, err := p.Connect(speed, spi.Mode0, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SPITransport{c: c, dc: dc, rst: rst, cs: cs}, nil
+}
+
+// Reset runs the hardware reset sequence on the RST pin
+func (t *SPITransport) Reset() error {
+	if err := t.rst.Out(gpio.High); err != nil {
+		return err
+	}
+	time.Sleep(1 * time.Millisecond)
+	if err := t.rst.Out(gpio.Low); err != nil {
+		return err
+	}
+	time.Sleep(1 * time.Millisecond)
+	if err := t.rst.Out(gpio.High); err != nil {
+		return err
+	}
+	time.Sleep(1 * time.Millisecond)
+	return nil
+}
+
+// Command sends command bytes with D/C low
+func (t *SPITransport) Command(cmds ...byte) error {
+	return t.tx(gpio.Low, cmds)
+}
+
+// Data sends data bytes with D/C high
+func (t *SPITransport) Data(data []byte) error {
+	return t.tx(gpio.High, data)
+}
+
+// tx selects the chip and writes b with the given D/C level
+func (t *SPITransport) tx(dc gpio.Level, b []byte) error {
+	if err := t.dc.Out(dc); err != nil {
+		return err
+	}
+	if err := t.cs.Out(gpio.Low); err != nil {
+		return err
+	}
+	defer t.cs.Out(gpio.High)
+
+	return t.c.Tx(b, nil)
+}
+
+// String implements fmt.Stringer
+func (t *SPITransport) String() string {
+	return t.c.String()
+}
+
+// I2C control bytes prefixing every transaction
+const (
+	i2cControlCommand = 0x00
+	i2cControlData    = 0x40
+)
+
+// I2CTransport drives a display over I2C, selecting commands or data with a control byte
+type I2CTransport struct {
+	d   *i2c.Dev
+	buf []byte
+}
+
+// NewI2CTransport returns a transport for the display at addr on bus b
+func NewI2CTransport(b i2c.Bus, addr uint16) *I2CTransport {
+	return &I2CTransport{d: &i2c.Dev{Bus: b, Addr: addr}}
+}
+
+// Command sends command bytes in a single transaction
+func (t *I2CTransport) Command(cmds ...byte) error {
+	return t.tx(i2cControlCommand, cmds)
+}
+
+// Data sends data bytes in a single transaction
+func (t *I2CTransport) Data(data []byte) error {
+	return t.tx(i2cControlData, data)
+}
+
+// Status reads the controller status byte. Not every I2C adapter or module
+// supports reads, in which case an error is returned.
+func (t *I2CTransport) Status() (byte, error) {
+	var r [1]byte
+	if err := t.d.Tx(nil, r[:]); err != nil {
+		return 0, err
+	}
+	return r[0], nil
+}
+
+// tx prefixes b with the control byte and writes it
+func (t *I2CTransport) tx(control byte, b []byte) error {
+	t.buf = append(t.buf[:0], control)
+	t.buf = append(t.buf, b...)
+	return t.d.Tx(t.buf, nil)
+}
+
+// String implements fmt.Stringer
+func (t *I2CTransport) String() string {
+	return t.d.String()
+}
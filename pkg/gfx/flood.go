@@ -0,0 +1,59 @@
+package gfx
+
+import (
+	"image"
+	"image/draw"
+)
+
+// FloodFill applies ink to the 4-connected region of pixels that share the
+// lit state of the seed pixel, like a paint bucket
+func FloodFill(dst draw.Image, seed image.Point, ink Ink) {
+	bounds := dst.Bounds()
+	if ink == None || !seed.In(bounds) {
+		return
+	}
+
+	target := lit(dst, seed.X, seed.Y)
+	if (ink == On && target) || (ink == Off && !target) {
+		return
+	}
+
+	// Track visited pixels so XOR does not flip a pixel back
+	p := newPainter(dst, bounds)
+	visited := p.fill
+	match := func(x, y int) bool {
+		return !visited.GetPixel(x, y) && lit(dst, x, y) == target
+	}
+
+	stack := []image.Point{seed}
+	for len(stack) > 0 {
+		pt := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !match(pt.X, pt.Y) {
+			continue
+		}
+
+		// Extend the run left and right, then queue the rows above and below
+		x0, x1 := pt.X, pt.X
+		for x0-1 >= bounds.Min.X && match(x0-1, pt.Y) {
+			x0--
+		}
+		for x1+1 < bounds.Max.X && match(x1+1, pt.Y) {
+			x1++
+		}
+		p.span(pt.Y, x0, x1)
+
+		for _, y := range [2]int{pt.Y - 1, pt.Y + 1} {
+			if y < bounds.Min.Y || y >= bounds.Max.Y {
+				continue
+			}
+			for x := x0; x <= x1; x++ {
+				if match(x, y) && (x == x0 || !match(x-1, y)) {
+					stack = append(stack, image.Pt(x, y))
+				}
+			}
+		}
+	}
+
+	p.ink(Fill(ink))
+}
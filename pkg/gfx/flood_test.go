@@ -0,0 +1,34 @@
+package gfx
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestFloodFill(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 16, 16))
+	Rect(img, image.Rect(2, 2, 10, 10), Stroke(On))
+
+	FloodFill(img, image.Pt(5, 5), On)
+	if raster.Count(img, img.Rect) != 64 {
+		t.Errorf("Expected the box to be filled up to its outline, got %d pixels", raster.Count(img, img.Rect))
+	}
+	if img.GetPixel(0, 0) {
+		t.Error("Expected the fill not to leak outside the box")
+	}
+
+	// XOR flips the region of the seed only once
+	FloodFill(img, image.Pt(0, 0), XOR)
+	if raster.Count(img, img.Rect) != 16*16 {
+		t.Errorf("Expected the whole canvas to be lit, got %d pixels", raster.Count(img, img.Rect))
+	}
+
+	// Filling a region that already has the ink is a no-op
+	FloodFill(img, image.Pt(0, 0), On)
+	if raster.Count(img, img.Rect) != 16*16 {
+		t.Errorf("Expected no change, got %d pixels", raster.Count(img, img.Rect))
+	}
+}
@@ -0,0 +1,278 @@
+// Package gfx provides graphics primitives for monochrome canvases.
+package gfx
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// Ink tells how a primitive changes the pixels it covers
+type Ink int
+
+const (
+	// None leaves pixels untouched
+	None Ink = iota
+	// On lights pixels
+	On
+	// Off darkens pixels
+	Off
+	// XOR flips pixels
+	XOR
+)
+
+// Style selects the inks used for the outline and the interior of a shape.
+// A shape with only Fill set is filled including its outline.
+type Style struct {
+	Stroke Ink
+	Fill   Ink
+
+	// Pattern limits the fill to the pixels set in the pattern, nil filling
+	// every pixel. The outline is always drawn solid.
+	Pattern *Pattern
+	// Anchor aligns the pattern to the screen or to the shape
+	Anchor Anchor
+}
+
+// Stroke returns a style that only draws the outline
+func Stroke(ink Ink) Style {
+	return Style{Stroke: ink}
+}
+
+// Fill returns a style that only fills the shape
+func Fill(ink Ink) Style {
+	return Style{Fill: ink}
+}
+
+// Bitmap is implemented by 1-bit canvases such as *mono.Image and
+// *display.SH1106. Primitives use it to read and write lit pixels directly;
+// other draw.Image canvases are treated as lit where they are white.
+type Bitmap interface {
+	GetPixel(x, y int) bool
+	SetPixel(x, y int, on bool)
+}
+
+// lit reports whether the pixel of dst is lit
+func lit(dst draw.Image, x, y int) bool {
+	if b, ok := dst.(Bitmap); ok {
+		return b.GetPixel(x, y)
+	}
+	return color.GrayModel.Convert(dst.At(x, y)).(color.Gray).Y >= 0x80
+}
+
+// setLit lights or darkens the pixel of dst
+func setLit(dst draw.Image, x, y int, on bool) {
+	if b, ok := dst.(Bitmap); ok {
+		b.SetPixel(x, y, on)
+		return
+	}
+	if on {
+		dst.Set(x, y, color.White)
+	} else {
+		dst.Set(x, y, color.Black)
+	}
+}
+
+// apply changes a single pixel of dst with ink
+func apply(dst draw.Image, x, y int, ink Ink) {
+	switch ink {
+	case On:
+		setLit(dst, x, y, true)
+	case Off:
+		setLit(dst, x, y, false)
+	case XOR:
+		setLit(dst, x, y, !lit(dst, x, y))
+	}
+}
+
+// Pixel changes a single pixel
+func Pixel(dst draw.Image, x, y int, ink Ink) {
+	if image.Pt(x, y).In(dst.Bounds()) {
+		apply(dst, x, y, ink)
+	}
+}
+
+// Mask changes the pixels of dst under the lit pixels of m, placed with its
+// top-left corner at p, e.g. to draw icons and glyphs
+func Mask(dst draw.Image, p image.Point, m *mono.Image, ink Ink) {
+	clip := dst.Bounds()
+	for y := m.Rect.Min.Y; y < m.Rect.Max.Y; y++ {
+		for x := m.Rect.Min.X; x < m.Rect.Max.X; x++ {
+			if !m.GetPixel(x, y) {
+				continue
+			}
+			q := p.Add(image.Pt(x, y).Sub(m.Rect.Min))
+			if q.In(clip) {
+				apply(dst, q.X, q.Y, ink)
+			}
+		}
+	}
+}
+
+// clipped is a view of an image that ignores pixels outside a rectangle
+type clipped struct {
+	draw.Image
+	r image.Rectangle
+}
+
+// Clip returns a view of dst limited to r, e.g. so that a widget drawing
+// labels cannot spill out of its rectangle
+func Clip(dst draw.Image, r image.Rectangle) draw.Image {
+	return &clipped{Image: dst, r: r.Intersect(dst.Bounds())}
+}
+
+// Bounds implements image.Image
+func (c *clipped) Bounds() image.Rectangle {
+	return c.r
+}
+
+// Set implements draw.Image
+func (c *clipped) Set(x, y int, col color.Color) {
+	if image.Pt(x, y).In(c.r) {
+		c.Image.Set(x, y, col)
+	}
+}
+
+// GetPixel implements Bitmap
+func (c *clipped) GetPixel(x, y int) bool {
+	return image.Pt(x, y).In(c.r) && lit(c.Image, x, y)
+}
+
+// SetPixel implements Bitmap
+func (c *clipped) SetPixel(x, y int, on bool) {
+	if image.Pt(x, y).In(c.r) {
+		setLit(c.Image, x, y, on)
+	}
+}
+
+// painter collects the coverage of one primitive before inking it, so that
+// every pixel is touched once and XOR does not cancel itself out where the
+// outline overlaps or meets the interior
+type painter struct {
+	dst     draw.Image
+	clip    image.Rectangle
+	outline *mono.Image
+	fill    *mono.Image
+	box     image.Rectangle
+	// origin is the top-left corner of the unclipped shape, where patterns
+	// anchored to the shape start
+	origin image.Point
+}
+
+// newPainter returns a painter for a shape of dst lying within bounds. The
+// coverage buffers only span bounds clipped to dst, so small shapes on a
+// large canvas stay cheap.
+func newPainter(dst draw.Image, bounds image.Rectangle) *painter {
+	clip := dst.Bounds().Intersect(bounds)
+	return &painter{
+		dst:     dst,
+		clip:    clip,
+		outline: mono.New(clip),
+		fill:    mono.New(clip),
+	}
+}
+
+// grow extends the dirty box with the pixel at x, y
+func (p *painter) grow(x, y int) {
+	r := image.Rect(x, y, x+1, y+1)
+	if p.box.Empty() {
+		p.box = r
+	} else {
+		p.box = p.box.Union(r)
+	}
+}
+
+// plot marks an outline pixel
+func (p *painter) plot(x, y int) {
+	if !image.Pt(x, y).In(p.clip) {
+		return
+	}
+	p.outline.SetPixel(x, y, true)
+	p.grow(x, y)
+}
+
+// span marks the interior pixels from x0 to x1 inclusive on row y
+func (p *painter) span(y, x0, x1 int) {
+	if y < p.clip.Min.Y || y >= p.clip.Max.Y {
+		return
+	}
+	x0 = max(x0, p.clip.Min.X)
+	x1 = min(x1, p.clip.Max.X-1)
+	if x0 > x1 {
+		return
+	}
+	for x := x0; x <= x1; x++ {
+		p.fill.SetPixel(x, y, true)
+	}
+	p.grow(x0, y)
+	p.grow(x1, y)
+}
+
+// line marks the outline of a line with Bresenham's algorithm
+func (p *painter) line(x0, y0, x1, y1 int) {
+	p.dashed(x0, y0, x1, y1, 1, 0)
+}
+
+// dashed marks a line made of dash pixels on and gap pixels off
+func (p *painter) dashed(x0, y0, x1, y1, dash, gap int) {
+	dx := raster.Abs(x1 - x0)
+	dy := -raster.Abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for i := 0; ; i++ {
+		if i%(dash+gap) < dash {
+			p.plot(x0, y0)
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// ink applies the style to the collected coverage
+func (p *painter) ink(s Style) {
+	var anchor image.Point
+	if s.Anchor == AnchorShape {
+		anchor = p.origin
+	}
+
+	for y := p.box.Min.Y; y < p.box.Max.Y; y++ {
+		for x := p.box.Min.X; x < p.box.Max.X; x++ {
+			edge := p.outline.GetPixel(x, y)
+			inside := edge || p.fill.GetPixel(x, y)
+
+			switch {
+			case edge && s.Stroke != None:
+				apply(p.dst, x, y, s.Stroke)
+			case inside && s.Fill != None:
+				if s.Pattern == nil || s.Pattern.Set(x-anchor.X, y-anchor.Y) {
+					apply(p.dst, x, y, s.Fill)
+				}
+			}
+		}
+	}
+}
+
+// box returns the smallest rectangle holding the pixels (x0, y0) and (x1, y1)
+func box(x0, y0, x1, y1 int) image.Rectangle {
+	return image.Rect(min(x0, x1), min(y0, y1), max(x0, x1)+1, max(y0, y1)+1)
+}
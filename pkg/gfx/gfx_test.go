@@ -0,0 +1,122 @@
+package gfx
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestPixelInks(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 8, 8))
+
+	Pixel(img, 1, 1, On)
+	if !img.GetPixel(1, 1) {
+		t.Error("Expected On to light the pixel")
+	}
+
+	Pixel(img, 1, 1, XOR)
+	if img.GetPixel(1, 1) {
+		t.Error("Expected XOR to flip the pixel off")
+	}
+
+	Pixel(img, 1, 1, XOR)
+	Pixel(img, 1, 1, Off)
+	if img.GetPixel(1, 1) {
+		t.Error("Expected Off to darken the pixel")
+	}
+
+	Pixel(img, 100, 100, On) // Out of bounds is ignored
+}
+
+func TestGrayCanvas(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+
+	Line(img, 0, 0, 7, 0, On)
+	if img.GrayAt(3, 0) != (color.Gray{Y: 0xFF}) {
+		t.Error("Expected lit pixels to be white on a gray canvas")
+	}
+
+	Line(img, 0, 0, 7, 0, XOR)
+	if img.GrayAt(3, 0) != (color.Gray{Y: 0}) {
+		t.Error("Expected XOR to darken white pixels")
+	}
+}
+
+func TestXORShapeTouchesEachPixelOnce(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+
+	Circle(img, image.Pt(16, 16), 10, Style{Stroke: XOR, Fill: XOR})
+	filled := mono.New(image.Rect(0, 0, 32, 32))
+	Circle(filled, image.Pt(16, 16), 10, Fill(On))
+
+	if raster.Count(img, img.Rect) != raster.Count(filled, filled.Rect) {
+		t.Errorf("Expected XOR stroke and fill to light %d pixels, got %d", raster.Count(filled, filled.Rect), raster.Count(img, img.Rect))
+	}
+}
+
+func TestMask(t *testing.T) {
+	m := mono.New(image.Rect(0, 0, 3, 3))
+	m.SetPixel(0, 0, true)
+	m.SetPixel(2, 2, true)
+
+	img := mono.New(image.Rect(0, 0, 8, 8))
+	img.SetPixel(6, 6, true)
+	Mask(img, image.Pt(4, 4), m, XOR)
+	if !img.GetPixel(4, 4) || img.GetPixel(6, 6) || raster.Count(img, img.Rect) != 1 {
+		t.Errorf("Expected the mask to flip (4, 4) and (6, 6), got %d lit pixels", raster.Count(img, img.Rect))
+	}
+
+	// Clipped at the canvas edge
+	img.Fill(false)
+	Mask(img, image.Pt(6, 6), m, On)
+	if !img.GetPixel(6, 6) || raster.Count(img, img.Rect) != 1 {
+		t.Errorf("Expected only (6, 6) lit, got %d lit pixels", raster.Count(img, img.Rect))
+	}
+}
+
+func TestClip(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 16, 16))
+	clip := Clip(img, image.Rect(4, 4, 8, 20))
+	if want := image.Rect(4, 4, 8, 16); clip.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, clip.Bounds())
+	}
+
+	Line(clip, 0, 6, 15, 6, On)
+	if got := raster.Count(img, img.Rect); got != 4 {
+		t.Errorf("Expected 4 pixels inside the clip, got %d", got)
+	}
+	if !img.GetPixel(4, 6) || img.GetPixel(3, 6) || img.GetPixel(8, 6) {
+		t.Error("Expected only the columns of the clip to be drawn")
+	}
+
+	clip.Set(0, 0, color.White)
+	if img.GetPixel(0, 0) {
+		t.Error("Expected Set outside the clip to be ignored")
+	}
+}
+
+func TestPainterBuffersFitShape(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 1024, 1024))
+
+	p := newPainter(img, box(10, 20, 3, 25))
+	if want := image.Rect(3, 20, 11, 26); p.outline.Rect != want || p.fill.Rect != want {
+		t.Errorf("Expected buffers covering %v, got %v and %v", want, p.outline.Rect, p.fill.Rect)
+	}
+
+	// Shapes partly outside the canvas only get buffers for the visible part
+	p = newPainter(img, image.Rect(-50, 1000, 10, 1100))
+	if want := image.Rect(0, 1000, 10, 1024); p.clip != want || p.outline.Rect != want {
+		t.Errorf("Expected buffers clipped to %v, got %v", want, p.outline.Rect)
+	}
+}
+
+func BenchmarkLine(b *testing.B) {
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		Line(img, i%128, 10, (i+3)%128, 14, XOR)
+	}
+}
@@ -0,0 +1,296 @@
+package gfx
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"slices"
+)
+
+// FillRule tells which areas enclosed by a path are inside it
+type FillRule int
+
+const (
+	// NonZero fills every area the outline winds around, the SVG default
+	NonZero FillRule = iota
+	// EvenOdd fills areas enclosed an odd number of times, leaving holes
+	// where subpaths overlap, like Polygon
+	EvenOdd
+)
+
+// vec is a point with sub-pixel precision
+type vec struct {
+	x, y float64
+}
+
+// subpath is a flattened run of connected segments
+type subpath struct {
+	pts    []vec
+	closed bool
+}
+
+// Path is a vector outline made of straight and curved segments, e.g. for
+// logos and smooth gauges. Like the other primitives, integer coordinates are
+// pixel centers. Curves are flattened to short lines as they are added.
+type Path struct {
+	subpaths []subpath
+	cur      vec
+}
+
+// flatness is the largest distance in pixels between a curve and the lines
+// approximating it
+const flatness = 0.2
+
+// MoveTo starts a new subpath at x, y
+func (p *Path) MoveTo(x, y float64) {
+	p.cur = vec{x, y}
+	p.subpaths = append(p.subpaths, subpath{pts: []vec{p.cur}})
+}
+
+// open returns the subpath being built, starting one at the current point
+// after Close or on an empty path
+func (p *Path) open() *subpath {
+	if n := len(p.subpaths); n > 0 && !p.subpaths[n-1].closed {
+		return &p.subpaths[n-1]
+	}
+	p.subpaths = append(p.subpaths, subpath{pts: []vec{p.cur}})
+	return &p.subpaths[len(p.subpaths)-1]
+}
+
+// LineTo adds a straight segment from the current point to x, y
+func (p *Path) LineTo(x, y float64) {
+	s := p.open()
+	p.cur = vec{x, y}
+	s.pts = append(s.pts, p.cur)
+}
+
+// QuadTo adds a quadratic Bézier curve to x, y with control point cx, cy
+func (p *Path) QuadTo(cx, cy, x, y float64) {
+	s := p.open()
+	p0, c, p1 := p.cur, vec{cx, cy}, vec{x, y}
+	// The deviation of a quadratic curve is a quarter of the control offset
+	dev := math.Hypot(p0.x-2*c.x+p1.x, p0.y-2*c.y+p1.y) / 4
+	n := segments(dev)
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		u := 1 - t
+		s.pts = append(s.pts, vec{
+			u*u*p0.x + 2*u*t*c.x + t*t*p1.x,
+			u*u*p0.y + 2*u*t*c.y + t*t*p1.y,
+		})
+	}
+	p.cur = p1
+}
+
+// CubicTo adds a cubic Bézier curve to x, y with control points c1x, c1y and
+// c2x, c2y
+func (p *Path) CubicTo(c1x, c1y, c2x, c2y, x, y float64) {
+	s := p.open()
+	p0, c1, c2, p1 := p.cur, vec{c1x, c1y}, vec{c2x, c2y}, vec{x, y}
+	// Bound the deviation by the second differences of the control points
+	dev := 0.75 * max(
+		math.Hypot(p0.x-2*c1.x+c2.x, p0.y-2*c1.y+c2.y),
+		math.Hypot(c1.x-2*c2.x+p1.x, c1.y-2*c2.y+p1.y),
+	)
+	n := segments(dev)
+	for i := 1; i <= n; i++ {
+		t := float64(i) / float64(n)
+		u := 1 - t
+		s.pts = append(s.pts, vec{
+			u*u*u*p0.x + 3*u*u*t*c1.x + 3*u*t*t*c2.x + t*t*t*p1.x,
+			u*u*u*p0.y + 3*u*u*t*c1.y + 3*u*t*t*c2.y + t*t*t*p1.y,
+		})
+	}
+	p.cur = p1
+}
+
+// segments returns how many lines keep a curve deviating by dev within
+// flatness, the error shrinking with the square of the count
+func segments(dev float64) int {
+	return max(1, min(int(math.Ceil(math.Sqrt(dev/flatness))), 256))
+}
+
+// Close ends the current subpath with a line back to its first point
+func (p *Path) Close() {
+	n := len(p.subpaths)
+	if n == 0 || p.subpaths[n-1].closed {
+		return
+	}
+	s := &p.subpaths[n-1]
+	s.closed = true
+	p.cur = s.pts[0]
+}
+
+// Current returns the current point, where the next segment starts
+func (p *Path) Current() (x, y float64) {
+	return p.cur.x, p.cur.y
+}
+
+// Bounds returns the pixels covered by the points of the path
+func (p *Path) Bounds() image.Rectangle {
+	var r image.Rectangle
+	for _, s := range p.subpaths {
+		for _, pt := range s.pts {
+			px := image.Pt(int(math.Floor(pt.x+0.5)), int(math.Floor(pt.y+0.5)))
+			r = r.Union(image.Rectangle{Min: px, Max: px.Add(image.Pt(1, 1))})
+		}
+	}
+	return r
+}
+
+// DrawPath draws a path. The stroke follows every subpath with lines width
+// pixels wide, with round joins and caps, and the fill covers the pixels
+// whose centers are inside the closed subpaths according to rule.
+func DrawPath(dst draw.Image, path *Path, s Style, width float64, rule FillRule) {
+	// Round caps and joins reach half the width past the points
+	bounds := path.Bounds()
+	p := newPainter(dst, bounds.Inset(-int(math.Ceil(max(width, 1)/2))))
+	p.origin = bounds.Min
+
+	if s.Fill != None {
+		var polys [][]vec
+		for _, sp := range path.subpaths {
+			polys = append(polys, sp.pts)
+		}
+		scan(polys, rule, p.clip, p.span)
+	}
+	if s.Stroke != None {
+		for _, sp := range path.subpaths {
+			stroke(p, sp, width)
+		}
+	}
+
+	p.ink(s)
+}
+
+// stroke marks the outline of a subpath
+func stroke(p *painter, s subpath, width float64) {
+	pts := s.pts
+	if len(pts) < 2 {
+		// A lone MoveTo draws nothing
+		return
+	}
+	if s.closed {
+		pts = append(slices.Clip(pts), pts[0])
+	}
+
+	if width <= 1 {
+		round := func(v vec) (int, int) {
+			return int(math.Floor(v.x + 0.5)), int(math.Floor(v.y + 0.5))
+		}
+		x0, y0 := round(pts[0])
+		p.plot(x0, y0)
+		for _, pt := range pts[1:] {
+			x1, y1 := round(pt)
+			p.line(x0, y0, x1, y1)
+			x0, y0 = x1, y1
+		}
+		return
+	}
+
+	r := width / 2
+	mark := func(y, x0, x1 int) {
+		for x := x0; x <= x1; x++ {
+			p.plot(x, y)
+		}
+	}
+	for i, a := range pts {
+		// Round joins and caps
+		disk(a, r, p.clip, mark)
+		if i == 0 {
+			continue
+		}
+
+		// A rectangle around the segment
+		b := pts[i-1]
+		l := math.Hypot(b.x-a.x, b.y-a.y)
+		if l == 0 {
+			continue
+		}
+		nx, ny := (b.y-a.y)/l*r, (a.x-b.x)/l*r
+		quad := []vec{{a.x + nx, a.y + ny}, {b.x + nx, b.y + ny}, {b.x - nx, b.y - ny}, {a.x - nx, a.y - ny}}
+		scan([][]vec{quad}, NonZero, p.clip, mark)
+	}
+}
+
+// disk calls span for the rows of pixels whose centers lie within r of c
+func disk(c vec, r float64, clip image.Rectangle, span func(y, x0, x1 int)) {
+	y0 := max(int(math.Ceil(c.y-r)), clip.Min.Y)
+	y1 := min(int(math.Floor(c.y+r)), clip.Max.Y-1)
+	for y := y0; y <= y1; y++ {
+		dx := math.Sqrt(max(0, r*r-(float64(y)-c.y)*(float64(y)-c.y)))
+		x0 := max(int(math.Ceil(c.x-dx)), clip.Min.X)
+		x1 := min(int(math.Floor(c.x+dx)), clip.Max.X-1)
+		if x0 <= x1 {
+			span(y, x0, x1)
+		}
+	}
+}
+
+// crossing is where an edge crosses a scanline
+type crossing struct {
+	x   float64
+	dir int
+}
+
+// scan calls span for the rows of pixels whose centers are inside the
+// polygons, each one implicitly closed
+func scan(polys [][]vec, rule FillRule, clip image.Rectangle, span func(y, x0, x1 int)) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, pts := range polys {
+		for _, pt := range pts {
+			lo = min(lo, pt.y)
+			hi = max(hi, pt.y)
+		}
+	}
+	if lo > hi {
+		return
+	}
+	y0 := max(int(math.Ceil(lo)), clip.Min.Y)
+	y1 := min(int(math.Floor(hi)), clip.Max.Y-1)
+
+	var xs []crossing
+	for y := y0; y <= y1; y++ {
+		cy := float64(y)
+		xs = xs[:0]
+		for _, pts := range polys {
+			for i, a := range pts {
+				b := pts[(i+1)%len(pts)]
+				if (a.y <= cy) == (b.y <= cy) {
+					continue
+				}
+				dir := 1
+				if b.y < a.y {
+					dir = -1
+				}
+				xs = append(xs, crossing{a.x + (cy-a.y)*(b.x-a.x)/(b.y-a.y), dir})
+			}
+		}
+		slices.SortFunc(xs, func(a, b crossing) int {
+			switch {
+			case a.x < b.x:
+				return -1
+			case a.x > b.x:
+				return 1
+			}
+			return 0
+		})
+
+		winding := 0
+		for i := 0; i+1 < len(xs); i++ {
+			winding += xs[i].dir
+			inside := winding != 0
+			if rule == EvenOdd {
+				inside = (i+1)%2 == 1
+			}
+			if !inside {
+				continue
+			}
+			x0 := max(int(math.Ceil(xs[i].x)), clip.Min.X)
+			x1 := min(int(math.Ceil(xs[i+1].x))-1, clip.Max.X-1)
+			if x0 <= x1 {
+				span(y, x0, x1)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package gfx
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// square returns a closed square path from x, y with side n
+func square(p *Path, x, y, n float64) {
+	p.MoveTo(x, y)
+	p.LineTo(x+n, y)
+	p.LineTo(x+n, y+n)
+	p.LineTo(x, y+n)
+	p.Close()
+}
+
+func TestPathFill(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+
+	// Pixel centers inside the square are filled, the far edges excluded
+	p := &Path{}
+	square(p, 2, 2, 8)
+	DrawPath(img, p, Fill(On), 1, NonZero)
+	if raster.Count(img, img.Rect) != 64 || !img.GetPixel(2, 2) || !img.GetPixel(9, 9) || img.GetPixel(10, 10) {
+		t.Errorf("Expected an 8x8 square, got %d pixels", raster.Count(img, img.Rect))
+	}
+	if got := p.Bounds(); got != image.Rect(2, 2, 11, 11) {
+		t.Errorf("Expected bounds (2,2)-(11,11), got %v", got)
+	}
+}
+
+func TestPathFillRules(t *testing.T) {
+	// An inner square wound the same way is a hole only with even-odd
+	p := &Path{}
+	square(p, 0, 0, 16)
+	square(p, 4, 4, 8)
+
+	img := mono.New(image.Rect(0, 0, 32, 32))
+	DrawPath(img, p, Fill(On), 1, NonZero)
+	if raster.Count(img, img.Rect) != 256 {
+		t.Errorf("NonZero: Expected 256 pixels, got %d", raster.Count(img, img.Rect))
+	}
+
+	img.Fill(false)
+	DrawPath(img, p, Fill(On), 1, EvenOdd)
+	if raster.Count(img, img.Rect) != 256-64 || img.GetPixel(8, 8) {
+		t.Errorf("EvenOdd: Expected a hole, got %d pixels", raster.Count(img, img.Rect))
+	}
+
+	// Winding the inner square backwards makes a hole either way
+	p = &Path{}
+	square(p, 0, 0, 16)
+	p.MoveTo(4, 4)
+	p.LineTo(4, 12)
+	p.LineTo(12, 12)
+	p.LineTo(12, 4)
+	p.Close()
+	img.Fill(false)
+	DrawPath(img, p, Fill(On), 1, NonZero)
+	if img.GetPixel(8, 8) || !img.GetPixel(1, 1) {
+		t.Error("NonZero: Expected a hole for the reversed subpath")
+	}
+}
+
+func TestPathCurves(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 64, 64))
+
+	// A circle of radius 20 from four cubic arcs
+	const k = 0.5523 * 20
+	p := &Path{}
+	p.MoveTo(52, 32)
+	p.CubicTo(52, 32+k, 32+k, 52, 32, 52)
+	p.CubicTo(32-k, 52, 12, 32+k, 12, 32)
+	p.CubicTo(12, 32-k, 32-k, 12, 32, 12)
+	p.CubicTo(32+k, 12, 52, 32-k, 52, 32)
+	p.Close()
+	DrawPath(img, p, Fill(On), 1, NonZero)
+
+	// About pi * 20^2 pixels
+	if n, want := raster.Count(img, img.Rect), int(math.Round(math.Pi*20*20)); n < want*97/100 || n > want*103/100 {
+		t.Errorf("Expected about %d pixels, got %d", want, n)
+	}
+
+	// A quadratic curve passes through the middle of its hull
+	img.Fill(false)
+	q := &Path{}
+	q.MoveTo(0, 40)
+	q.QuadTo(20, 0, 40, 40)
+	DrawPath(img, q, Stroke(On), 1, NonZero)
+	if !img.GetPixel(20, 20) || !img.GetPixel(0, 40) || !img.GetPixel(40, 40) {
+		t.Error("Expected the curve through (20, 20) and its ends")
+	}
+	// The stroke is connected, one pixel per row on the steep parts
+	for y := 21; y <= 40; y++ {
+		row := 0
+		for x := 0; x < 20; x++ {
+			if img.GetPixel(x, y) {
+				row++
+			}
+		}
+		if row == 0 {
+			t.Errorf("Expected the curve to cross row %d", y)
+		}
+	}
+}
+
+func TestPathStrokeWidth(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+
+	p := &Path{}
+	p.MoveTo(4, 16)
+	p.LineTo(27, 16)
+	DrawPath(img, p, Stroke(On), 5, NonZero)
+
+	// 5 rows thick along the line, with round caps
+	for y := 14; y <= 18; y++ {
+		if !img.GetPixel(16, y) {
+			t.Errorf("Expected (16, %d) in the stroke", y)
+		}
+	}
+	if img.GetPixel(16, 13) || img.GetPixel(16, 19) {
+		t.Error("Expected the stroke to be 5 pixels thick")
+	}
+	if !img.GetPixel(2, 16) || img.GetPixel(2, 14) || img.GetPixel(1, 16) {
+		t.Error("Expected a round cap reaching 2 pixels past the end")
+	}
+
+	// Stroke and fill together touch each pixel once with XOR
+	img.Fill(false)
+	s := &Path{}
+	square(s, 4, 4, 16)
+	DrawPath(img, s, Style{Stroke: XOR, Fill: XOR}, 3, NonZero)
+	if !img.GetPixel(4, 4) || !img.GetPixel(12, 12) || !img.GetPixel(3, 12) {
+		t.Error("Expected XOR to cover the stroke and the interior")
+	}
+}
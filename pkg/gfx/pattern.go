@@ -0,0 +1,73 @@
+package gfx
+
+// Pattern is an 8x8 fill pattern tiled over the plane, one byte per row with
+// bit 7 being the leftmost pixel. Fills only ink the pixels set in the
+// pattern and leave the others untouched, so shades are laid over whatever
+// was drawn before; erase the area first for an opaque shade.
+type Pattern [8]byte
+
+// Predefined patterns
+var (
+	// Checker alternates single pixels, the same as Gray(8)
+	Checker = Pattern{0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55}
+	// Hatch draws diagonal lines rising to the right
+	Hatch = Pattern{0x01, 0x02, 0x04, 0x08, 0x10, 0x20, 0x40, 0x80}
+	// BackHatch draws diagonal lines falling to the right
+	BackHatch = Pattern{0x80, 0x40, 0x20, 0x10, 0x08, 0x04, 0x02, 0x01}
+	// CrossHatch overlays Hatch and BackHatch
+	CrossHatch = Pattern{0x81, 0x42, 0x24, 0x18, 0x18, 0x24, 0x42, 0x81}
+	// Dots sets one pixel in every 4x4 cell
+	Dots = Pattern{0x88, 0x00, 0x00, 0x00, 0x88, 0x00, 0x00, 0x00}
+)
+
+// GrayLevels is the number of steps between an empty and a full Gray pattern
+const GrayLevels = 16
+
+// bayer4 is the 4x4 ordered dithering index matrix
+var bayer4 = [4][4]int{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// Gray returns an evenly spread pattern with level out of GrayLevels pixels
+// set, from 0 for empty to 16 for full. Levels are clamped to that range.
+// Each level sets the pixels of the level below plus new ones, so gradients
+// built from neighbouring levels do not shimmer.
+func Gray(level int) Pattern {
+	level = max(0, min(level, GrayLevels))
+	var p Pattern
+	for y := range p {
+		for x := 0; x < 8; x++ {
+			if bayer4[y&3][x&3] < level {
+				p[y] |= 0x80 >> x
+			}
+		}
+	}
+	return p
+}
+
+// Set reports whether the pattern covers the pixel at x, y. Coordinates wrap
+// around, so any point of the plane can be looked up.
+func (p Pattern) Set(x, y int) bool {
+	return p[y&7]&(0x80>>(x&7)) != 0
+}
+
+// Anchor tells where the pattern tiling starts
+type Anchor int
+
+const (
+	// AnchorScreen aligns the pattern to the canvas origin, so neighbouring
+	// shapes join seamlessly
+	AnchorScreen Anchor = iota
+	// AnchorShape aligns the pattern to the top-left corner of the shape's
+	// bounding box, so a shape looks the same wherever it is drawn
+	AnchorShape
+)
+
+// FillPattern returns a style that fills the shape through a pattern aligned
+// to the screen
+func FillPattern(ink Ink, p Pattern) Style {
+	return Style{Fill: ink, Pattern: &p}
+}
@@ -0,0 +1,100 @@
+package gfx
+
+import (
+	"image"
+	"math/bits"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestGray(t *testing.T) {
+	prev := Gray(0)
+	for level := 0; level <= GrayLevels; level++ {
+		p := Gray(level)
+		n := 0
+		for y, row := range p {
+			n += bits.OnesCount8(row)
+			if row&prev[y] != prev[y] {
+				t.Errorf("Level %d drops pixels of level %d", level, level-1)
+			}
+		}
+		if n != level*4 {
+			t.Errorf("Level %d: Expected %d pixels, got %d", level, level*4, n)
+		}
+		prev = p
+	}
+	if Gray(8) != Checker {
+		t.Errorf("Expected Gray(8) to be the checker, got %x", Gray(8))
+	}
+	if Gray(-3) != Gray(0) || Gray(99) != Gray(GrayLevels) {
+		t.Error("Expected levels to be clamped")
+	}
+}
+
+func TestPatternSet(t *testing.T) {
+	if !Hatch.Set(7, 0) || Hatch.Set(0, 0) || !Hatch.Set(0, 7) {
+		t.Error("Expected the hatch to rise to the right")
+	}
+	// Coordinates wrap, negative ones included
+	if Hatch.Set(-1, 0) != Hatch.Set(7, 0) || Dots.Set(4, -4) != Dots.Set(4, 4) {
+		t.Error("Expected coordinates to wrap")
+	}
+}
+
+func TestPatternFill(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+
+	// Screen anchored, the pattern lines up with the canvas
+	Rect(img, image.Rect(3, 5, 19, 21), FillPattern(On, Checker))
+	if raster.Count(img, img.Rect) != 128 {
+		t.Errorf("Expected 128 pixels, got %d", raster.Count(img, img.Rect))
+	}
+	for y := 5; y < 21; y++ {
+		for x := 3; x < 19; x++ {
+			if img.GetPixel(x, y) != Checker.Set(x, y) {
+				t.Fatalf("Pixel (%d, %d) does not follow the screen aligned pattern", x, y)
+			}
+		}
+	}
+
+	// Shape anchored, the pattern starts at the corner of the shape
+	img.Fill(false)
+	s := FillPattern(On, Dots)
+	s.Anchor = AnchorShape
+	Rect(img, image.Rect(3, 5, 19, 21), s)
+	if !img.GetPixel(3, 5) || img.GetPixel(0, 4) || raster.Count(img, img.Rect) != 16 {
+		t.Errorf("Expected the dots to start at (3, 5), got %d pixels", raster.Count(img, img.Rect))
+	}
+
+	// The outline stays solid, the pattern is laid over existing pixels
+	img.Fill(false)
+	img.SetPixel(10, 10, true)
+	Rect(img, image.Rect(8, 8, 16, 16), Style{Stroke: On, Fill: On, Pattern: &Dots})
+	if raster.Count(img, img.Rect) != 28+1+1 {
+		t.Errorf("Expected 28 outline pixels, one dot and the old pixel, got %d", raster.Count(img, img.Rect))
+	}
+
+	// Circles and polygons take patterns too
+	img.Fill(false)
+	Circle(img, image.Pt(16, 16), 10, FillPattern(On, Gray(4)))
+	full := mono.New(img.Rect)
+	Circle(full, image.Pt(16, 16), 10, Fill(On))
+	if n, all := raster.Count(img, img.Rect), raster.Count(full, full.Rect); n < all/5 || n > all/3 {
+		t.Errorf("Expected a quarter of %d pixels, got %d", all, n)
+	}
+
+	img.Fill(false)
+	s = FillPattern(XOR, Hatch)
+	s.Anchor = AnchorShape
+	Polygon(img, []image.Point{{20, 4}, {27, 4}, {27, 11}, {20, 11}}, s)
+	for i := 0; i < 8; i++ {
+		if !img.GetPixel(27-i, 4+i) {
+			t.Errorf("Expected the hatch diagonal at (%d, %d)", 27-i, 4+i)
+		}
+	}
+	if raster.Count(img, img.Rect) != 8 {
+		t.Errorf("Expected 8 pixels, got %d", raster.Count(img, img.Rect))
+	}
+}
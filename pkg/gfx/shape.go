@@ -0,0 +1,285 @@
+package gfx
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"slices"
+)
+
+// Line draws a line from (x0, y0) to (x1, y1), both ends included
+func Line(dst draw.Image, x0, y0, x1, y1 int, ink Ink) {
+	p := newPainter(dst, box(x0, y0, x1, y1))
+	p.line(x0, y0, x1, y1)
+	p.ink(Stroke(ink))
+}
+
+// DashedLine draws a line alternating dash pixels drawn and gap pixels skipped
+func DashedLine(dst draw.Image, x0, y0, x1, y1, dash, gap int, ink Ink) {
+	if dash <= 0 {
+		return
+	}
+	p := newPainter(dst, box(x0, y0, x1, y1))
+	p.dashed(x0, y0, x1, y1, dash, max(gap, 0))
+	p.ink(Stroke(ink))
+}
+
+// Rect draws the rectangle r; r.Max is exclusive like image.Rectangle
+func Rect(dst draw.Image, r image.Rectangle, s Style) {
+	RoundRect(dst, r, 0, s)
+}
+
+// RoundRect draws the rectangle r with corners rounded by radius
+func RoundRect(dst draw.Image, r image.Rectangle, radius int, s Style) {
+	r = r.Canon()
+	if r.Empty() {
+		return
+	}
+
+	x0, y0, x1, y1 := r.Min.X, r.Min.Y, r.Max.X-1, r.Max.Y-1
+	radius = max(0, min(radius, (min(r.Dx(), r.Dy())-1)/2))
+
+	p := newPainter(dst, r)
+	p.origin = r.Min
+	h := newHull(y0, y1)
+	plot := func(x, y int) {
+		p.plot(x, y)
+		h.add(x, y)
+	}
+
+	// Straight edges
+	for x := x0 + radius; x <= x1-radius; x++ {
+		plot(x, y0)
+		plot(x, y1)
+	}
+	for y := y0 + radius; y <= y1-radius; y++ {
+		plot(x0, y)
+		plot(x1, y)
+	}
+
+	// Corners
+	if radius > 0 {
+		ellipseQuadrant(radius, radius, func(dx, dy int) {
+			plot(x0+radius-dx, y0+radius-dy)
+			plot(x1-radius+dx, y0+radius-dy)
+			plot(x0+radius-dx, y1-radius+dy)
+			plot(x1-radius+dx, y1-radius+dy)
+		})
+	}
+
+	h.fill(p)
+	p.ink(s)
+}
+
+// Circle draws a circle centered on c
+func Circle(dst draw.Image, c image.Point, radius int, s Style) {
+	Ellipse(dst, c, radius, radius, s)
+}
+
+// Ellipse draws an axis aligned ellipse centered on c
+func Ellipse(dst draw.Image, c image.Point, rx, ry int, s Style) {
+	if rx < 0 || ry < 0 {
+		return
+	}
+
+	p := newPainter(dst, box(c.X-rx, c.Y-ry, c.X+rx, c.Y+ry))
+	p.origin = c.Sub(image.Pt(rx, ry))
+	if rx == 0 || ry == 0 {
+		p.line(c.X-rx, c.Y-ry, c.X+rx, c.Y+ry)
+		p.ink(s)
+		return
+	}
+
+	h := newHull(c.Y-ry, c.Y+ry)
+	ellipseQuadrant(rx, ry, func(dx, dy int) {
+		for _, pt := range [4]image.Point{{c.X + dx, c.Y + dy}, {c.X - dx, c.Y + dy}, {c.X + dx, c.Y - dy}, {c.X - dx, c.Y - dy}} {
+			p.plot(pt.X, pt.Y)
+			h.add(pt.X, pt.Y)
+		}
+	})
+
+	h.fill(p)
+	p.ink(s)
+}
+
+// Arc draws the part of a circle centered on c going counterclockwise from
+// start to end degrees, with 0 pointing right and 90 pointing up
+func Arc(dst draw.Image, c image.Point, radius int, start, end float64, ink Ink) {
+	if radius < 0 {
+		return
+	}
+
+	sweep := end - start
+	full := sweep >= 360 || sweep <= -360
+	sweep = normDeg(sweep)
+
+	p := newPainter(dst, box(c.X-radius, c.Y-radius, c.X+radius, c.Y+radius))
+	ellipseQuadrant(radius, radius, func(dx, dy int) {
+		for _, d := range [4]image.Point{{dx, dy}, {-dx, dy}, {dx, -dy}, {-dx, -dy}} {
+			a := math.Atan2(float64(-d.Y), float64(d.X)) * 180 / math.Pi
+			if full || normDeg(a-start) <= sweep {
+				p.plot(c.X+d.X, c.Y+d.Y)
+			}
+		}
+	})
+	p.ink(Stroke(ink))
+}
+
+// Triangle draws the triangle with the given corners
+func Triangle(dst draw.Image, p0, p1, p2 image.Point, s Style) {
+	Polygon(dst, []image.Point{p0, p1, p2}, s)
+}
+
+// Polygon draws a closed polygon. The interior follows the even-odd rule,
+// so self-intersecting polygons get holes.
+func Polygon(dst draw.Image, pts []image.Point, s Style) {
+	if len(pts) == 0 {
+		return
+	}
+
+	bounds := box(pts[0].X, pts[0].Y, pts[0].X, pts[0].Y)
+	for _, a := range pts {
+		bounds = bounds.Union(box(a.X, a.Y, a.X, a.Y))
+	}
+
+	p := newPainter(dst, bounds)
+	p.origin = bounds.Min
+	for i, a := range pts {
+		b := pts[(i+1)%len(pts)]
+		p.line(a.X, a.Y, b.X, b.Y)
+	}
+
+	if s.Fill != None {
+		scanPolygon(p, pts)
+	}
+	p.ink(s)
+}
+
+// scanPolygon marks the interior of pts sampling pixel centers
+func scanPolygon(p *painter, pts []image.Point) {
+	y0, y1 := pts[0].Y, pts[0].Y
+	for _, pt := range pts {
+		y0 = min(y0, pt.Y)
+		y1 = max(y1, pt.Y)
+	}
+	y0 = max(y0, p.clip.Min.Y)
+	y1 = min(y1, p.clip.Max.Y-1)
+
+	var xs []float64
+	for y := y0; y <= y1; y++ {
+		cy := float64(y) + 0.5
+		xs = xs[:0]
+		for i, a := range pts {
+			b := pts[(i+1)%len(pts)]
+			ay, by := float64(a.Y)+0.5, float64(b.Y)+0.5
+			if (ay <= cy) == (by <= cy) {
+				continue
+			}
+			t := (cy - ay) / (by - ay)
+			xs = append(xs, float64(a.X)+0.5+t*float64(b.X-a.X))
+		}
+		slices.Sort(xs)
+
+		for i := 0; i+1 < len(xs); i += 2 {
+			p.span(y, int(math.Ceil(xs[i]-0.5)), int(math.Floor(xs[i+1]-0.5)))
+		}
+	}
+}
+
+// ellipseQuadrant calls plot with the offsets of one quadrant of an ellipse
+// using the midpoint algorithm
+func ellipseQuadrant(rx, ry int, plot func(dx, dy int)) {
+	if rx == 0 || ry == 0 {
+		for dx := 0; dx <= rx; dx++ {
+			plot(dx, 0)
+		}
+		for dy := 0; dy <= ry; dy++ {
+			plot(0, dy)
+		}
+		return
+	}
+
+	rx2 := float64(rx * rx)
+	ry2 := float64(ry * ry)
+	x, y := 0, ry
+	dx := 0.0
+	dy := 2 * rx2 * float64(y)
+
+	// Region 1, slope above -1
+	d1 := ry2 - rx2*float64(ry) + rx2/4
+	for dx < dy {
+		plot(x, y)
+		x++
+		dx += 2 * ry2
+		if d1 < 0 {
+			d1 += dx + ry2
+		} else {
+			y--
+			dy -= 2 * rx2
+			d1 += dx - dy + ry2
+		}
+	}
+
+	// Region 2, slope below -1
+	fx, fy := float64(x)+0.5, float64(y-1)
+	d2 := ry2*fx*fx + rx2*fy*fy - rx2*ry2
+	for y >= 0 {
+		plot(x, y)
+		y--
+		dy -= 2 * rx2
+		if d2 > 0 {
+			d2 += rx2 - dy
+		} else {
+			x++
+			dx += 2 * ry2
+			d2 += dx - dy + rx2
+		}
+	}
+}
+
+// normDeg wraps an angle into [0, 360)
+func normDeg(a float64) float64 {
+	a = math.Mod(a, 360)
+	if a < 0 {
+		a += 360
+	}
+	return a
+}
+
+// hull records the leftmost and rightmost outline pixel of every row of a
+// convex shape, so it can be filled with one span per row
+type hull struct {
+	y0    int
+	left  []int
+	right []int
+}
+
+// newHull returns a hull for rows y0 to y1 inclusive
+func newHull(y0, y1 int) *hull {
+	n := max(y1-y0+1, 0)
+	h := &hull{y0: y0, left: make([]int, n), right: make([]int, n)}
+	for i := range h.left {
+		h.left[i] = math.MaxInt
+		h.right[i] = math.MinInt
+	}
+	return h
+}
+
+// add records an outline pixel
+func (h *hull) add(x, y int) {
+	i := y - h.y0
+	if i < 0 || i >= len(h.left) {
+		return
+	}
+	h.left[i] = min(h.left[i], x)
+	h.right[i] = max(h.right[i], x)
+}
+
+// fill marks the span of every row as interior
+func (h *hull) fill(p *painter) {
+	for i := range h.left {
+		if h.left[i] <= h.right[i] {
+			p.span(h.y0+i, h.left[i], h.right[i])
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package gfx
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestLine(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 16, 16))
+
+	Line(img, 0, 0, 15, 5, On)
+	if !img.GetPixel(0, 0) || !img.GetPixel(15, 5) {
+		t.Error("Expected both ends of the line to be lit")
+	}
+	if raster.Count(img, img.Rect) != 16 {
+		t.Errorf("Expected 16 pixels, got %d", raster.Count(img, img.Rect))
+	}
+}
+
+func TestDashedLine(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 16, 16))
+
+	DashedLine(img, 0, 0, 9, 0, 2, 3, On)
+	for x, want := range []bool{true, true, false, false, false, true, true, false, false, false} {
+		if img.GetPixel(x, 0) != want {
+			t.Errorf("Pixel (%d, 0): expected %v", x, want)
+		}
+	}
+}
+
+func TestRect(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 16, 16))
+
+	Rect(img, image.Rect(2, 2, 7, 6), Stroke(On))
+	if raster.Count(img, img.Rect) != 2*5+2*2 {
+		t.Errorf("Expected 14 outline pixels, got %d", raster.Count(img, img.Rect))
+	}
+	if img.GetPixel(4, 4) {
+		t.Error("Expected the interior to be empty")
+	}
+
+	Rect(img, image.Rect(2, 2, 7, 6), Fill(On))
+	if raster.Count(img, img.Rect) != 20 {
+		t.Errorf("Expected 20 filled pixels, got %d", raster.Count(img, img.Rect))
+	}
+
+	// Fill only the interior, keep the outline dark
+	img.Fill(false)
+	Rect(img, image.Rect(2, 2, 7, 6), Style{Stroke: Off, Fill: On})
+	if raster.Count(img, img.Rect) != 6 || img.GetPixel(2, 2) {
+		t.Errorf("Expected 6 interior pixels, got %d", raster.Count(img, img.Rect))
+	}
+}
+
+func TestRoundRect(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+
+	RoundRect(img, image.Rect(0, 0, 20, 10), 4, Stroke(On))
+	if img.GetPixel(0, 0) || img.GetPixel(19, 9) {
+		t.Error("Expected corners to be rounded")
+	}
+	if !img.GetPixel(10, 0) || !img.GetPixel(0, 5) || !img.GetPixel(19, 5) || !img.GetPixel(10, 9) {
+		t.Error("Expected straight edges to be lit")
+	}
+}
+
+func TestCircle(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+
+	Circle(img, image.Pt(16, 16), 8, Stroke(On))
+	for _, p := range []image.Point{{24, 16}, {8, 16}, {16, 24}, {16, 8}} {
+		if !img.GetPixel(p.X, p.Y) {
+			t.Errorf("Expected extreme point %v to be lit", p)
+		}
+	}
+	if img.GetPixel(16, 16) || img.GetPixel(25, 16) {
+		t.Error("Expected center and outside to be dark")
+	}
+
+	// Symmetric around the center
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			if img.GetPixel(x, y) != img.GetPixel(32-x, y) || img.GetPixel(x, y) != img.GetPixel(y, x) {
+				t.Fatalf("Circle is not symmetric at (%d, %d)", x, y)
+			}
+		}
+	}
+}
+
+func TestEllipse(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+
+	Ellipse(img, image.Pt(16, 16), 12, 4, Fill(On))
+	if !img.GetPixel(4, 16) || !img.GetPixel(28, 16) || !img.GetPixel(16, 12) || !img.GetPixel(16, 20) {
+		t.Error("Expected ellipse extremes to be lit")
+	}
+	if img.GetPixel(16, 11) || img.GetPixel(3, 16) {
+		t.Error("Expected pixels outside the ellipse to be dark")
+	}
+}
+
+func TestArc(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+
+	// Upper half only
+	Arc(img, image.Pt(16, 16), 8, 0, 180, On)
+	if !img.GetPixel(16, 8) {
+		t.Error("Expected the top of the arc to be lit")
+	}
+	if img.GetPixel(16, 24) {
+		t.Error("Expected the bottom of the circle to be dark")
+	}
+}
+
+func TestPolygon(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 32, 32))
+
+	Triangle(img, image.Pt(0, 0), image.Pt(10, 0), image.Pt(0, 10), Fill(On))
+	if !img.GetPixel(2, 2) || !img.GetPixel(10, 0) || !img.GetPixel(0, 10) {
+		t.Error("Expected triangle interior and corners to be lit")
+	}
+	if img.GetPixel(8, 8) {
+		t.Error("Expected pixels beyond the hypotenuse to be dark")
+	}
+
+	// A self intersecting star has an empty center under the even-odd rule
+	img.Fill(false)
+	star := []image.Point{{16, 0}, {26, 30}, {0, 11}, {31, 11}, {6, 30}}
+	Polygon(img, star, Fill(On))
+	if img.GetPixel(16, 16) {
+		t.Error("Expected the center of the star to be a hole")
+	}
+	if !img.GetPixel(16, 5) {
+		t.Error("Expected the tips of the star to be filled")
+	}
+}
+
+func TestClipping(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 16, 16))
+
+	Circle(img, image.Pt(0, 0), 30, Fill(On))
+	if raster.Count(img, img.Rect) != 16*16 {
+		t.Errorf("Expected the whole canvas to be filled, got %d pixels", raster.Count(img, img.Rect))
+	}
+}
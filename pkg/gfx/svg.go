@@ -0,0 +1,214 @@
+package gfx
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParsePath builds a path from SVG path data, the d attribute of a <path>
+// element. It supports the M, L, H, V, C, S, Q, T and Z commands in absolute
+// and relative form; elliptical arcs are not supported. Coordinates are used
+// as they are, so scale the artwork to the canvas when exporting it.
+func ParsePath(d string) (*Path, error) {
+	l := &pathLexer{s: d}
+	p := &Path{}
+
+	var cmd byte
+	// Reflected control point for S and T, valid after a curve of that kind
+	var ctrl vec
+	var last byte
+
+	for {
+		c, ok := l.command()
+		if !ok {
+			if l.done() {
+				return p, nil
+			}
+			if cmd == 0 {
+				return nil, fmt.Errorf("gfx: path data must start with a command at offset %d", l.i)
+			}
+			return nil, fmt.Errorf("gfx: unexpected %q in path data at offset %d", l.s[l.i], l.i)
+		}
+		cmd = c
+		if cmd != 'M' && cmd != 'm' && len(p.subpaths) == 0 {
+			return nil, fmt.Errorf("gfx: path data must start with a moveto, got %q", cmd)
+		}
+
+		for first := true; first || l.number(); first = false {
+			rel := cmd >= 'a'
+			upper := cmd &^ 0x20
+			if upper == 'Z' {
+				if !first {
+					break
+				}
+				p.Close()
+				last = 'Z'
+				continue
+			}
+
+			args, err := l.args(map[byte]int{'M': 2, 'L': 2, 'H': 1, 'V': 1, 'C': 6, 'S': 4, 'Q': 4, 'T': 2}[upper])
+			if err != nil {
+				return nil, err
+			}
+			if upper == 0 || args == nil {
+				return nil, fmt.Errorf("gfx: unsupported path command %q", cmd)
+			}
+
+			// Resolve relative coordinates against the current point
+			cx, cy := p.Current()
+			at := func(i int) vec {
+				if rel {
+					return vec{cx + args[i], cy + args[i+1]}
+				}
+				return vec{args[i], args[i+1]}
+			}
+			reflect := func(kind byte) vec {
+				if last == kind {
+					return vec{2*cx - ctrl.x, 2*cy - ctrl.y}
+				}
+				return vec{cx, cy}
+			}
+
+			switch upper {
+			case 'M':
+				pt := at(0)
+				p.MoveTo(pt.x, pt.y)
+				// Further pairs are implicit linetos
+				cmd = 'L' | cmd&0x20
+			case 'L':
+				pt := at(0)
+				p.LineTo(pt.x, pt.y)
+			case 'H':
+				x := args[0]
+				if rel {
+					x += cx
+				}
+				p.LineTo(x, cy)
+			case 'V':
+				y := args[0]
+				if rel {
+					y += cy
+				}
+				p.LineTo(cx, y)
+			case 'C':
+				c1, c2, pt := at(0), at(2), at(4)
+				p.CubicTo(c1.x, c1.y, c2.x, c2.y, pt.x, pt.y)
+				ctrl = c2
+			case 'S':
+				c1, c2, pt := reflect('C'), at(0), at(2)
+				p.CubicTo(c1.x, c1.y, c2.x, c2.y, pt.x, pt.y)
+				ctrl = c2
+			case 'Q':
+				c, pt := at(0), at(2)
+				p.QuadTo(c.x, c.y, pt.x, pt.y)
+				ctrl = c
+			case 'T':
+				c, pt := reflect('Q'), at(0)
+				p.QuadTo(c.x, c.y, pt.x, pt.y)
+				ctrl = c
+			}
+
+			// S continues a cubic curve and T a quadratic one
+			switch upper {
+			case 'C', 'S':
+				last = 'C'
+			case 'Q', 'T':
+				last = 'Q'
+			default:
+				last = upper
+			}
+		}
+	}
+}
+
+// pathLexer splits SVG path data into commands and numbers
+type pathLexer struct {
+	s string
+	i int
+}
+
+// skip moves past whitespace and commas
+func (l *pathLexer) skip() {
+	for l.i < len(l.s) {
+		switch l.s[l.i] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			l.i++
+		default:
+			return
+		}
+	}
+}
+
+// done reports whether all the data was read
+func (l *pathLexer) done() bool {
+	l.skip()
+	return l.i >= len(l.s)
+}
+
+// command reads a command letter
+func (l *pathLexer) command() (byte, bool) {
+	l.skip()
+	if l.i >= len(l.s) {
+		return 0, false
+	}
+	c := l.s[l.i]
+	if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') || c == 'e' || c == 'E' {
+		return 0, false
+	}
+	l.i++
+	return c, true
+}
+
+// number reports whether a number comes next
+func (l *pathLexer) number() bool {
+	l.skip()
+	if l.i >= len(l.s) {
+		return false
+	}
+	c := l.s[l.i]
+	return c >= '0' && c <= '9' || c == '-' || c == '+' || c == '.'
+}
+
+// args reads n numbers, returning nil for n = 0
+func (l *pathLexer) args(n int) ([]float64, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	args := make([]float64, n)
+	for k := range args {
+		if !l.number() {
+			return nil, fmt.Errorf("gfx: expected a number in path data at offset %d", l.i)
+		}
+		start := l.i
+		if c := l.s[l.i]; c == '-' || c == '+' {
+			l.i++
+		}
+		l.digits()
+		// A second dot starts the next number, as in "0.5.5"
+		if l.i < len(l.s) && l.s[l.i] == '.' {
+			l.i++
+			l.digits()
+		}
+		if l.i < len(l.s) && (l.s[l.i] == 'e' || l.s[l.i] == 'E') {
+			l.i++
+			if l.i < len(l.s) && (l.s[l.i] == '-' || l.s[l.i] == '+') {
+				l.i++
+			}
+			l.digits()
+		}
+
+		v, err := strconv.ParseFloat(l.s[start:l.i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("gfx: invalid number %q in path data", l.s[start:l.i])
+		}
+		args[k] = v
+	}
+	return args, nil
+}
+
+// digits moves past a run of decimal digits
+func (l *pathLexer) digits() {
+	for l.i < len(l.s) && l.s[l.i] >= '0' && l.s[l.i] <= '9' {
+		l.i++
+	}
+}
@@ -0,0 +1,79 @@
+package gfx
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestParsePath(t *testing.T) {
+	draw := func(d string) *mono.Image {
+		p, err := ParsePath(d)
+		if err != nil {
+			t.Fatalf("ParsePath(%q) failed: %v", d, err)
+		}
+		img := mono.New(image.Rect(0, 0, 32, 32))
+		DrawPath(img, p, Fill(On), 1, NonZero)
+		return img
+	}
+
+	// The same square written in different ways
+	want := draw("M2 2 L10 2 L10 10 L2 10 Z")
+	for _, d := range []string{
+		"M2,2 10,2 10,10 2,10z",
+		"m2 2 h8 v8 h-8 z",
+		"M 2 2 H 10 V 10 H 2 Z",
+		"M2e0 .2e1L10 2l0 8-8 0Z",
+		"m2 2l8.0.0v8H2z",
+	} {
+		got := draw(d)
+		if raster.Count(got, got.Rect) != raster.Count(want, want.Rect) {
+			t.Errorf("%q: Expected %d pixels, got %d", d, raster.Count(want, want.Rect), raster.Count(got, got.Rect))
+		}
+	}
+	if raster.Count(want, want.Rect) != 64 {
+		t.Errorf("Expected 64 pixels, got %d", raster.Count(want, want.Rect))
+	}
+
+	// Relative moveto after close starts from the subpath start
+	p, err := ParsePath("M4 4 h4 v4 z m10 0 h4 v4 z")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.subpaths) != 2 || p.subpaths[1].pts[0] != (vec{14, 4}) {
+		t.Errorf("Expected a second subpath at (14, 4), got %v", p.subpaths)
+	}
+
+	// Smooth curves reflect the previous control point
+	c := draw("M4 16 C4 4 16 4 16 16 S28 28 28 16 Z")
+	s := draw("M4 16 C4 4 16 4 16 16 C16 28 28 28 28 16 Z")
+	if raster.Count(c, c.Rect) != raster.Count(s, s.Rect) {
+		t.Errorf("Expected S to match the explicit curve, got %d and %d pixels", raster.Count(c, c.Rect), raster.Count(s, s.Rect))
+	}
+	q := draw("M2 16 Q8 4 14 16 T26 16 Z")
+	r := draw("M2 16 Q8 4 14 16 Q20 28 26 16 Z")
+	if raster.Count(q, q.Rect) != raster.Count(r, r.Rect) {
+		t.Errorf("Expected T to match the explicit curve, got %d and %d pixels", raster.Count(q, q.Rect), raster.Count(r, r.Rect))
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	for _, d := range []string{
+		"L2 2",
+		"2 2",
+		"M2",
+		"M2 2 A5 5 0 0 1 10 10",
+		"M2 2 L3 x",
+		"M2 2 Z 4",
+		"M1e 2",
+	} {
+		if _, err := ParsePath(d); err == nil {
+			t.Errorf("%q: Expected an error", d)
+		}
+	}
+	if p, err := ParsePath("  "); err != nil || len(p.subpaths) != 0 {
+		t.Errorf("Expected an empty path, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package icon
+
+// art holds the hand drawn icons, '#' being lit. The arrows pointing down,
+// left and right are derived from arrow-up.
+var art = map[Size]map[string][]string{
+	Small: {
+		"wifi": {
+			"........",
+			".######.",
+			"#......#",
+			"..####..",
+			".#....#.",
+			"...##...",
+			"...##...",
+			"........",
+		},
+		"signal": {
+			"........",
+			"......#.",
+			"......#.",
+			"....#.#.",
+			"....#.#.",
+			"..#.#.#.",
+			"..#.#.#.",
+			"#.#.#.#.",
+		},
+		"lock": {
+			"..####..",
+			".#....#.",
+			".#....#.",
+			"########",
+			"###..###",
+			"###..###",
+			"########",
+			"########",
+		},
+		"unlock": {
+			"..####..",
+			".#....#.",
+			".#......",
+			"########",
+			"###..###",
+			"###..###",
+			"########",
+			"########",
+		},
+		"warning": {
+			"...##...",
+			"..####..",
+			"..#..#..",
+			".##..##.",
+			".######.",
+			"###..###",
+			"########",
+			"........",
+		},
+		"arrow-up": {
+			"...##...",
+			"..####..",
+			".######.",
+			"########",
+			"...##...",
+			"...##...",
+			"...##...",
+			"...##...",
+		},
+	},
+	Large: {
+		"wifi": {
+			"................",
+			"....########....",
+			"..##........##..",
+			".#............#.",
+			"#....######....#",
+			"...##......##...",
+			"..#..........#..",
+			"......####......",
+			"....##....##....",
+			"...#........#...",
+			"................",
+			".......##.......",
+			"......####......",
+			".......##.......",
+			"................",
+			"................",
+		},
+		"signal": {
+			"................",
+			"................",
+			"............###.",
+			"............###.",
+			"............###.",
+			"........###.###.",
+			"........###.###.",
+			"........###.###.",
+			"....###.###.###.",
+			"....###.###.###.",
+			"....###.###.###.",
+			"###.###.###.###.",
+			"###.###.###.###.",
+			"###.###.###.###.",
+			"###.###.###.###.",
+			"................",
+		},
+		"lock": {
+			"................",
+			".....######.....",
+			"....##....##....",
+			"....#......#....",
+			"....#......#....",
+			"....#......#....",
+			"..############..",
+			"..############..",
+			"..#####..#####..",
+			"..#####..#####..",
+			"..#####..#####..",
+			"..#####..#####..",
+			"..############..",
+			"..############..",
+			"..############..",
+			"................",
+		},
+		"unlock": {
+			"................",
+			".....######.....",
+			"....##....##....",
+			"....#......#....",
+			"....#...........",
+			"....#...........",
+			"..############..",
+			"..############..",
+			"..#####..#####..",
+			"..#####..#####..",
+			"..#####..#####..",
+			"..#####..#####..",
+			"..############..",
+			"..############..",
+			"..############..",
+			"................",
+		},
+		"warning": {
+			"................",
+			".......##.......",
+			"......####......",
+			"......####......",
+			".....######.....",
+			".....##..##.....",
+			"....###..###....",
+			"....###..###....",
+			"...####..####...",
+			"...####..####...",
+			"..#####..#####..",
+			"..############..",
+			".######..######.",
+			".######..######.",
+			"################",
+			"................",
+		},
+		"arrow-up": {
+			"................",
+			".......##.......",
+			"......####......",
+			".....######.....",
+			"....########....",
+			"...##########...",
+			"..############..",
+			".##############.",
+			"......####......",
+			"......####......",
+			"......####......",
+			"......####......",
+			"......####......",
+			"......####......",
+			"......####......",
+			"................",
+		},
+	},
+}
+
+// batteryArt is the battery outline, 'o' marking the charge area filled
+// from the left for battery-empty, battery-low, battery-half and battery-full
+var batteryArt = map[Size][]string{
+	Small: {
+		"........",
+		"#######.",
+		"#ooooo#.",
+		"#ooooo##",
+		"#ooooo##",
+		"#ooooo#.",
+		"#######.",
+		"........",
+	},
+	Large: {
+		"................",
+		"................",
+		"................",
+		"##############..",
+		"#............#..",
+		"#.oooooooooo.#..",
+		"#.oooooooooo.###",
+		"#.oooooooooo.###",
+		"#.oooooooooo.###",
+		"#.oooooooooo.###",
+		"#.oooooooooo.#..",
+		"#............#..",
+		"##############..",
+		"................",
+		"................",
+		"................",
+	},
+}
@@ -0,0 +1,146 @@
+// Package icon provides a built-in set of 8x8 and 16x16 status icons:
+// batteries, Wi-Fi, signal bars, locks, a warning sign and arrows.
+package icon
+
+import (
+	"image"
+	"image/draw"
+	"slices"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+	"github.com/danielgatis/go-sh1106/pkg/text"
+)
+
+// Size is the edge length of an icon in pixels
+type Size int
+
+const (
+	// Small icons are 8x8 and sit next to the embedded font
+	Small Size = 8
+	// Large icons are 16x16
+	Large Size = 16
+)
+
+// firstRune is the Unicode private use code point of the first icon
+const firstRune = 0xE000
+
+var (
+	names []string
+	icons = map[Size]map[string]*mono.Image{}
+)
+
+func init() {
+	for _, size := range []Size{Small, Large} {
+		set := map[string]*mono.Image{}
+		for name, rows := range art[size] {
+			set[name] = parse(rows)
+		}
+
+		levels := map[string]int{"battery-empty": 0, "battery-low": 1, "battery-half": 2, "battery-full": 4}
+		for name, level := range levels {
+			set[name] = battery(batteryArt[size], level)
+		}
+
+		up := set["arrow-up"]
+		set["arrow-down"] = raster.Remap(up, func(x, y int) (int, int) { return x, int(size) - 1 - y })
+		set["arrow-left"] = raster.Remap(up, func(x, y int) (int, int) { return y, x })
+		set["arrow-right"] = raster.Remap(up, func(x, y int) (int, int) { return y, int(size) - 1 - x })
+
+		icons[size] = set
+	}
+
+	for name := range icons[Small] {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+}
+
+// parse converts string art, '#' being lit, into an image
+func parse(rows []string) *mono.Image {
+	img := mono.New(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x, c := range row {
+			img.SetPixel(x, y, c == '#')
+		}
+	}
+	return img
+}
+
+// battery fills the 'o' columns of a battery outline from the left up to
+// level quarters
+func battery(rows []string, level int) *mono.Image {
+	x0, x1 := len(rows[0]), 0
+	for _, row := range rows {
+		for x, c := range row {
+			if c == 'o' {
+				x0, x1 = min(x0, x), max(x1, x+1)
+			}
+		}
+	}
+	fill := x0 + (x1-x0)*level/4
+	if level > 0 {
+		fill = max(fill, x0+1)
+	}
+
+	img := parse(rows)
+	for y, row := range rows {
+		for x, c := range row {
+			if c == 'o' && x < fill {
+				img.SetPixel(x, y, true)
+			}
+		}
+	}
+	return img
+}
+
+// Names returns the names of all icons, sorted
+func Names() []string {
+	return slices.Clone(names)
+}
+
+// Get returns the icon called name at the given size. The image is shared,
+// so it must not be modified.
+func Get(name string, size Size) (*mono.Image, bool) {
+	img, ok := icons[size][name]
+	return img, ok
+}
+
+// Draw paints the lit pixels of the icon called name with its top-left
+// corner at p. It reports whether the icon exists.
+func Draw(dst draw.Image, p image.Point, name string, size Size, ink gfx.Ink) bool {
+	img, ok := Get(name, size)
+	if ok {
+		gfx.Mask(dst, p, img, ink)
+	}
+	return ok
+}
+
+// Rune returns the private use rune standing for the icon called name in text
+// set up with Install, or 0 if there is no such icon
+func Rune(name string) rune {
+	i, ok := slices.BinarySearch(names, name)
+	if !ok {
+		return 0
+	}
+	return rune(firstRune + i)
+}
+
+// String returns the icon called name as a string for inline use in text
+func String(name string) string {
+	if c := Rune(name); c != 0 {
+		return string(c)
+	}
+	return ""
+}
+
+// Install makes the renderer draw the icons in place of their runes, e.g.
+//
+//	icon.Install(renderer, icon.Small)
+//	renderer.SetText(icon.String("wifi")+" connected", 0)
+func Install(r *text.Renderer, size Size) {
+	for _, name := range names {
+		r.SetGlyph(Rune(name), icons[size][name])
+	}
+}
@@ -0,0 +1,143 @@
+package icon
+
+import (
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+	"github.com/danielgatis/go-sh1106/pkg/text"
+)
+
+func TestAllSizes(t *testing.T) {
+	want := []string{
+		"arrow-down", "arrow-left", "arrow-right", "arrow-up",
+		"battery-empty", "battery-full", "battery-half", "battery-low",
+		"lock", "signal", "unlock", "warning", "wifi",
+	}
+	if !slices.Equal(Names(), want) {
+		t.Fatalf("Expected names %v, got %v", want, Names())
+	}
+
+	for _, size := range []Size{Small, Large} {
+		for _, name := range Names() {
+			img, ok := Get(name, size)
+			if !ok {
+				t.Errorf("Expected %s at size %d", name, size)
+				continue
+			}
+			if img.Rect != image.Rect(0, 0, int(size), int(size)) {
+				t.Errorf("Expected %s to be %dx%d, got %v", name, size, size, img.Rect)
+			}
+			if raster.Count(img, img.Rect) == 0 {
+				t.Errorf("Expected %s at size %d to have lit pixels", name, size)
+			}
+		}
+	}
+
+	if _, ok := Get("missing", Small); ok {
+		t.Error("Expected no icon for an unknown name")
+	}
+	if _, ok := Get("wifi", 12); ok {
+		t.Error("Expected no icon for an unknown size")
+	}
+}
+
+func TestArrowDirections(t *testing.T) {
+	for _, size := range []Size{Small, Large} {
+		s := int(size)
+		for name, head := range map[string]struct {
+			vertical bool
+			first    bool
+		}{
+			"arrow-up":    {true, true},
+			"arrow-down":  {true, false},
+			"arrow-left":  {false, true},
+			"arrow-right": {false, false},
+		} {
+			img, _ := Get(name, size)
+
+			// The widest row or column of the head lies on the side of the tip
+			widest, most := 0, 0
+			for i := 0; i < s; i++ {
+				n := 0
+				for j := 0; j < s; j++ {
+					if head.vertical && img.GetPixel(j, i) || !head.vertical && img.GetPixel(i, j) {
+						n++
+					}
+				}
+				if n > most {
+					widest, most = i, n
+				}
+			}
+			if (widest < s/2) != head.first {
+				t.Errorf("Expected %s at size %d to point to its tip", name, size)
+			}
+		}
+	}
+}
+
+func TestBatteryLevels(t *testing.T) {
+	for _, size := range []Size{Small, Large} {
+		prev := -1
+		for _, name := range []string{"battery-empty", "battery-low", "battery-half", "battery-full"} {
+			img, _ := Get(name, size)
+			if n := raster.Count(img, img.Rect); n <= prev {
+				t.Errorf("Expected %s at size %d to be fuller than the previous level", name, size)
+			} else {
+				prev = n
+			}
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	dst := mono.New(image.Rect(0, 0, 32, 32))
+	if !Draw(dst, image.Pt(4, 4), "lock", Small, gfx.On) {
+		t.Fatal("Expected lock to be drawn")
+	}
+
+	img, _ := Get("lock", Small)
+	if raster.Count(dst, dst.Rect) != raster.Count(img, img.Rect) || !dst.GetPixel(4+3, 4+3) {
+		t.Errorf("Expected the lock to be drawn at (4, 4)")
+	}
+
+	if Draw(dst, image.Point{}, "missing", Small, gfx.On) {
+		t.Error("Expected an unknown icon not to be drawn")
+	}
+}
+
+func TestRunes(t *testing.T) {
+	seen := map[rune]bool{}
+	for _, name := range Names() {
+		c := Rune(name)
+		if c < firstRune || seen[c] {
+			t.Errorf("Expected a unique private use rune for %s, got %U", name, c)
+		}
+		seen[c] = true
+	}
+	if Rune("missing") != 0 || String("missing") != "" {
+		t.Error("Expected no rune for an unknown icon")
+	}
+}
+
+func TestInstall(t *testing.T) {
+	r, err := text.NewRendererWithEmbeddedFont(&text.Config{Width: 64, Height: 16, LineCount: 1})
+	if err != nil {
+		t.Fatalf("Failed to create renderer: %v", err)
+	}
+	Install(r, Small)
+	r.SetText(String("battery-full"), 0)
+
+	// The icon stands on the baseline, the last row of the line
+	want, _ := Get("battery-full", Small)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if got := mono.LitIsWhite.Lit(r.Image().At(x, 7+y)); got != want.GetPixel(x, y) {
+				t.Fatalf("Expected the rendered icon to match at (%d, %d)", x, y)
+			}
+		}
+	}
+}
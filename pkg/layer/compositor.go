@@ -0,0 +1,140 @@
+package layer
+
+import (
+	"image"
+	"slices"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// Target is the surface layers are composited onto, such as *display.SH1106
+type Target interface {
+	Bounds() image.Rectangle
+	SetPixel(x, y int, on bool)
+	Update() error
+}
+
+// Compositor blends an ordered stack of layers, bottom first, onto a target.
+// Pixels not covered by any layer are dark.
+type Compositor struct {
+	target Target
+	layers []*Layer
+	dirty  image.Rectangle
+}
+
+// New returns a compositor for target. The whole target is composited on the
+// first Update.
+func New(target Target) *Compositor {
+	return &Compositor{
+		target: target,
+		dirty:  target.Bounds(),
+	}
+}
+
+// AddLayer creates a visible layer covering r in screen coordinates and puts
+// it on top of the stack. The layer pixels are addressed relative to r.Min,
+// which becomes the layer offset.
+func (c *Compositor) AddLayer(r image.Rectangle, op Op) *Layer {
+	l := &Layer{
+		c:       c,
+		img:     mono.New(image.Rect(0, 0, r.Dx(), r.Dy())),
+		op:      op,
+		offset:  r.Min,
+		visible: true,
+	}
+	c.layers = append(c.layers, l)
+	c.damage(r)
+	return l
+}
+
+// RemoveLayer takes a layer out of the stack
+func (c *Compositor) RemoveLayer(l *Layer) {
+	i := slices.Index(c.layers, l)
+	if i < 0 {
+		return
+	}
+	c.layers = slices.Delete(c.layers, i, i+1)
+	c.damage(l.screen(l.img.Rect))
+}
+
+// MoveLayer moves a layer to position i in the stack, 0 being the bottom
+func (c *Compositor) MoveLayer(l *Layer, i int) {
+	j := slices.Index(c.layers, l)
+	if j < 0 {
+		return
+	}
+	c.layers = slices.Delete(c.layers, j, j+1)
+	i = max(0, min(i, len(c.layers)))
+	c.layers = slices.Insert(c.layers, i, l)
+	c.damage(l.screen(l.img.Rect))
+}
+
+// Layers returns the stack, bottom first
+func (c *Compositor) Layers() []*Layer {
+	return slices.Clone(c.layers)
+}
+
+// Invalidate forces r, in screen coordinates, to be composited on the next Update
+func (c *Compositor) Invalidate(r image.Rectangle) {
+	c.damage(r)
+}
+
+// Update composites the changed regions into the target and refreshes it
+func (c *Compositor) Update() error {
+	c.Compose()
+	return c.target.Update()
+}
+
+// Compose composites the changed regions into the target without refreshing
+// it and returns the area that was redrawn
+func (c *Compositor) Compose() image.Rectangle {
+	dirty := c.dirty
+	for _, l := range c.layers {
+		if l.visible {
+			dirty = union(dirty, l.screen(l.dirty))
+		}
+		l.dirty = image.Rectangle{}
+	}
+	c.dirty = image.Rectangle{}
+
+	dirty = dirty.Intersect(c.target.Bounds())
+	for y := dirty.Min.Y; y < dirty.Max.Y; y++ {
+		for x := dirty.Min.X; x < dirty.Max.X; x++ {
+			c.target.SetPixel(x, y, c.pixel(x, y))
+		}
+	}
+
+	return dirty
+}
+
+// pixel blends the stack at a screen pixel
+func (c *Compositor) pixel(x, y int) bool {
+	on := false
+	for _, l := range c.layers {
+		if !l.visible {
+			continue
+		}
+		p := image.Pt(x, y).Sub(l.offset)
+		if !p.In(l.img.Rect) {
+			continue
+		}
+		on = l.op.blend(on, l.img.GetPixel(p.X, p.Y))
+	}
+	return on
+}
+
+// damage marks r, in screen coordinates, as changed
+func (c *Compositor) damage(r image.Rectangle) {
+	c.dirty = union(c.dirty, r)
+}
+
+// union is image.Rectangle.Union that ignores empty rectangles
+func union(a, b image.Rectangle) image.Rectangle {
+	if b.Empty() {
+		return a
+	}
+	if a.Empty() {
+		return b
+	}
+	return a.Union(b)
+}
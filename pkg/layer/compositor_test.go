@@ -0,0 +1,134 @@
+package layer
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// fakeTarget records the pixels written by the compositor
+type fakeTarget struct {
+	*mono.Image
+	writes  int
+	updates int
+}
+
+func newFakeTarget() *fakeTarget {
+	return &fakeTarget{Image: mono.New(image.Rect(0, 0, 32, 16))}
+}
+
+func (f *fakeTarget) SetPixel(x, y int, on bool) {
+	f.writes++
+	f.Image.SetPixel(x, y, on)
+}
+
+func (f *fakeTarget) Update() error {
+	f.updates++
+	return nil
+}
+
+func TestCompositorOps(t *testing.T) {
+	target := newFakeTarget()
+	c := New(target)
+
+	base := c.AddLayer(target.Bounds(), Copy)
+	base.SetPixel(0, 0, true)
+	base.SetPixel(1, 0, true)
+
+	top := c.AddLayer(image.Rect(0, 0, 4, 1), Xor)
+	top.SetPixel(1, 0, true)
+	top.SetPixel(2, 0, true)
+
+	if err := c.Update(); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	for x, want := range []bool{true, false, true, false} {
+		if target.GetPixel(x, 0) != want {
+			t.Errorf("Xor: pixel (%d, 0) expected %v", x, want)
+		}
+	}
+
+	top.SetOp(Or)
+	c.Update()
+	for x, want := range []bool{true, true, true, false} {
+		if target.GetPixel(x, 0) != want {
+			t.Errorf("Or: pixel (%d, 0) expected %v", x, want)
+		}
+	}
+
+	top.SetOp(And)
+	c.Update()
+	for x, want := range []bool{false, true, false, false} {
+		if target.GetPixel(x, 0) != want {
+			t.Errorf("And: pixel (%d, 0) expected %v", x, want)
+		}
+	}
+
+	top.SetOp(Copy)
+	top.SetVisible(false)
+	c.Update()
+	for x, want := range []bool{true, true, false, false} {
+		if target.GetPixel(x, 0) != want {
+			t.Errorf("Hidden: pixel (%d, 0) expected %v", x, want)
+		}
+	}
+}
+
+func TestCompositorOnlyChangedRegions(t *testing.T) {
+	target := newFakeTarget()
+	c := New(target)
+	base := c.AddLayer(target.Bounds(), Copy)
+
+	c.Update()
+	if target.writes != 32*16 {
+		t.Errorf("Expected a full first composite, got %d writes", target.writes)
+	}
+
+	target.writes = 0
+	gfx.Rect(base, image.Rect(2, 2, 5, 4), gfx.Fill(gfx.On))
+	if r := c.Compose(); r != image.Rect(2, 2, 5, 4) {
+		t.Errorf("Expected dirty rect (2,2)-(5,4), got %v", r)
+	}
+	if target.writes != 6 {
+		t.Errorf("Expected 6 writes, got %d", target.writes)
+	}
+
+	target.writes = 0
+	c.Update()
+	if target.writes != 0 {
+		t.Errorf("Expected no writes without changes, got %d", target.writes)
+	}
+}
+
+func TestCompositorMoveLayer(t *testing.T) {
+	target := newFakeTarget()
+	c := New(target)
+	c.AddLayer(target.Bounds(), Copy)
+
+	popup := c.AddLayer(image.Rect(10, 4, 14, 8), Copy)
+	popup.Fill(true)
+	c.Update()
+	if !target.GetPixel(10, 4) || target.GetPixel(9, 4) {
+		t.Error("Expected popup at its offset")
+	}
+
+	popup.SetOffset(image.Pt(0, 0))
+	c.Update()
+	if target.GetPixel(10, 4) || !target.GetPixel(0, 0) {
+		t.Error("Expected popup to move and the old area to be restored")
+	}
+
+	c.MoveLayer(popup, 0)
+	c.Update()
+	if target.GetPixel(0, 0) {
+		t.Error("Expected the opaque base to cover the popup once below it")
+	}
+
+	c.RemoveLayer(popup)
+	if len(c.Layers()) != 1 {
+		t.Errorf("Expected 1 layer, got %d", len(c.Layers()))
+	}
+}
@@ -0,0 +1,153 @@
+// Package layer composes a stack of monochrome layers onto a display.
+package layer
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// Op is the raster operation used to blend a layer with the layers below it
+type Op int
+
+const (
+	// Copy makes the layer opaque, e.g. for popups
+	Copy Op = iota
+	// Or only adds lit pixels, leaving the rest transparent, e.g. for toasts
+	Or
+	// And darkens pixels that are dark in the layer
+	And
+	// Xor flips pixels that are lit in the layer, e.g. for cursors
+	Xor
+)
+
+// blend combines the pixel below with the layer pixel
+func (op Op) blend(below, px bool) bool {
+	switch op {
+	case Or:
+		return below || px
+	case And:
+		return below && px
+	case Xor:
+		return below != px
+	}
+	return px
+}
+
+var _ draw.Image = (*Layer)(nil)
+
+// Layer is a monochrome canvas in a Compositor stack. It implements
+// draw.Image, so the gfx primitives and image/draw can paint on it, and it
+// remembers which area changed so only that part is composited again.
+type Layer struct {
+	c       *Compositor
+	img     *mono.Image
+	op      Op
+	offset  image.Point
+	visible bool
+	dirty   image.Rectangle
+}
+
+// Bounds returns the layer bounds in layer coordinates
+func (l *Layer) Bounds() image.Rectangle {
+	return l.img.Rect
+}
+
+// ColorModel returns the black and white color model
+func (l *Layer) ColorModel() color.Model {
+	return l.img.ColorModel()
+}
+
+// At returns the color of a layer pixel
+func (l *Layer) At(x, y int) color.Color {
+	return l.img.At(x, y)
+}
+
+// Set lights the pixel if c maps to a lit pixel
+func (l *Layer) Set(x, y int, c color.Color) {
+	l.SetPixel(x, y, l.img.Polarity.Lit(c))
+}
+
+// GetPixel reports whether a layer pixel is lit
+func (l *Layer) GetPixel(x, y int) bool {
+	return l.img.GetPixel(x, y)
+}
+
+// SetPixel lights or darkens a layer pixel
+func (l *Layer) SetPixel(x, y int, on bool) {
+	if l.img.GetPixel(x, y) == on || !image.Pt(x, y).In(l.img.Rect) {
+		return
+	}
+	l.img.SetPixel(x, y, on)
+	l.touch(image.Rect(x, y, x+1, y+1))
+}
+
+// Fill lights or darkens the whole layer
+func (l *Layer) Fill(on bool) {
+	l.img.Fill(on)
+	l.touch(l.img.Rect)
+}
+
+// Image returns the layer content. Changes made directly to it must be
+// reported with Invalidate.
+func (l *Layer) Image() *mono.Image {
+	return l.img
+}
+
+// Invalidate marks r, in layer coordinates, as changed
+func (l *Layer) Invalidate(r image.Rectangle) {
+	l.touch(r.Intersect(l.img.Rect))
+}
+
+// Op returns the blend operation
+func (l *Layer) Op() Op {
+	return l.op
+}
+
+// SetOp changes the blend operation
+func (l *Layer) SetOp(op Op) {
+	if op != l.op {
+		l.op = op
+		l.touch(l.img.Rect)
+	}
+}
+
+// Offset returns the screen position of the layer origin
+func (l *Layer) Offset() image.Point {
+	return l.offset
+}
+
+// SetOffset moves the layer so that its origin lands on p
+func (l *Layer) SetOffset(p image.Point) {
+	if p == l.offset {
+		return
+	}
+	l.c.damage(l.screen(l.img.Rect))
+	l.offset = p
+	l.touch(l.img.Rect)
+}
+
+// Visible reports whether the layer is composited
+func (l *Layer) Visible() bool {
+	return l.visible
+}
+
+// SetVisible shows or hides the layer
+func (l *Layer) SetVisible(visible bool) {
+	if visible != l.visible {
+		l.visible = visible
+		l.c.damage(l.screen(l.img.Rect))
+	}
+}
+
+// screen converts a rectangle from layer to screen coordinates
+func (l *Layer) screen(r image.Rectangle) image.Rectangle {
+	return r.Add(l.offset)
+}
+
+// touch extends the dirty area with r, in layer coordinates
+func (l *Layer) touch(r image.Rectangle) {
+	l.dirty = union(l.dirty, r)
+}
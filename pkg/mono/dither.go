@@ -0,0 +1,137 @@
+package mono
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// Dither selects how gray levels are turned into lit and unlit pixels
+type Dither int
+
+const (
+	// Threshold lights pixels on the lit side of 50% gray
+	Threshold Dither = iota
+	// FloydSteinberg diffuses the quantization error to the neighbouring
+	// pixels, giving the smoothest gradients for photos
+	FloydSteinberg
+	// Atkinson diffuses only part of the error, keeping more contrast
+	Atkinson
+	// Bayer compares pixels with an 8x8 ordered threshold matrix, which
+	// stays stable between animation frames
+	Bayer
+)
+
+var ditherNames = [...]string{
+	Threshold:      "threshold",
+	FloydSteinberg: "floyd-steinberg",
+	Atkinson:       "atkinson",
+	Bayer:          "bayer",
+}
+
+// String implements fmt.Stringer
+func (d Dither) String() string {
+	if d >= 0 && int(d) < len(ditherNames) {
+		return ditherNames[d]
+	}
+	return fmt.Sprintf("Dither(%d)", int(d))
+}
+
+// ParseDither returns the Dither named by s, as returned by String
+func ParseDither(s string) (Dither, error) {
+	for d, name := range ditherNames {
+		if s == name {
+			return Dither(d), nil
+		}
+	}
+	return Threshold, fmt.Errorf("mono: unknown dither %q", s)
+}
+
+// bayer8 is the 8x8 ordered dithering index matrix
+var bayer8 = [8][8]int{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+// diffusion is one neighbour receiving part of the quantization error
+type diffusion struct {
+	dx, dy int
+	weight int
+}
+
+var (
+	floydSteinberg = []diffusion{{1, 0, 7}, {-1, 1, 3}, {0, 1, 5}, {1, 1, 1}}
+	atkinson       = []diffusion{{1, 0, 1}, {2, 0, 1}, {-1, 1, 1}, {0, 1, 1}, {1, 1, 1}, {0, 2, 1}}
+)
+
+// Convert returns a mono image with the bounds of src, lighting pixels
+// according to the polarity and dithering method
+func Convert(src image.Image, p Polarity, d Dither) *Image {
+	r := src.Bounds()
+	dst := New(r)
+	dst.Polarity = p
+	w, h := r.Dx(), r.Dy()
+
+	// Intensity of every pixel on a 0-255 scale where 255 is fully lit
+	level := make([]int, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := int(color.GrayModel.Convert(src.At(r.Min.X+x, r.Min.Y+y)).(color.Gray).Y)
+			if p == LitIsBlack {
+				v = 0xFF - v
+			}
+			level[y*w+x] = v
+		}
+	}
+
+	var kernel []diffusion
+	div := 1
+	switch d {
+	case FloydSteinberg:
+		kernel, div = floydSteinberg, 16
+	case Atkinson:
+		kernel, div = atkinson, 8
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := level[y*w+x]
+
+			if d == Bayer {
+				// Thresholds are spread evenly between 0 and 255
+				t := (bayer8[(r.Min.Y+y)&7][(r.Min.X+x)&7]*2 + 1) * 0x100 / 128
+				if v >= t {
+					dst.SetPixel(r.Min.X+x, r.Min.Y+y, true)
+				}
+				continue
+			}
+
+			on := v >= 0x80
+			if on {
+				dst.SetPixel(r.Min.X+x, r.Min.Y+y, true)
+			}
+			if kernel == nil {
+				continue
+			}
+
+			e := v
+			if on {
+				e -= 0xFF
+			}
+			for _, k := range kernel {
+				nx, ny := x+k.dx, y+k.dy
+				if nx >= 0 && nx < w && ny < h {
+					level[ny*w+nx] += e * k.weight / div
+				}
+			}
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,106 @@
+package mono
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gray returns a w x h image of a single gray level
+func gray(w, h int, y uint8) *image.Gray {
+	g := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range g.Pix {
+		g.Pix[i] = y
+	}
+	return g
+}
+
+// lit counts the lit pixels of m
+func lit(m *Image) int {
+	n := 0
+	for y := m.Rect.Min.Y; y < m.Rect.Max.Y; y++ {
+		for x := m.Rect.Min.X; x < m.Rect.Max.X; x++ {
+			if m.GetPixel(x, y) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestConvertThresholdMatchesPolarity(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 4, 1))
+	copy(src.Pix, []uint8{0x00, 0x7F, 0x80, 0xFF})
+
+	for _, p := range []Polarity{LitIsWhite, LitIsBlack} {
+		m := Convert(src, p, Threshold)
+		if m.Polarity != p {
+			t.Errorf("Expected polarity %v, got %v", p, m.Polarity)
+		}
+		for x := 0; x < 4; x++ {
+			if want := p.Lit(src.At(x, 0)); m.GetPixel(x, 0) != want {
+				t.Errorf("%s: Expected pixel %d to be %v", p, x, want)
+			}
+		}
+	}
+}
+
+func TestConvertDitherDensity(t *testing.T) {
+	for _, d := range []Dither{FloydSteinberg, Atkinson, Bayer} {
+		for _, level := range []uint8{0x00, 0x40, 0x80, 0xC0, 0xFF} {
+			m := Convert(gray(64, 64, level), LitIsWhite, d)
+			want := int(level) * 64 * 64 / 0xFF
+			// Atkinson drops a quarter of the error, so allow some slack
+			if got := lit(m); got < want-64*64/8 || got > want+64*64/8 {
+				t.Errorf("%s at %#x: Expected about %d lit pixels, got %d", d, level, want, got)
+			}
+		}
+	}
+
+	// Ordered dithering of 50% gray lights exactly half of the pixels
+	if got := lit(Convert(gray(16, 16, 0x80), LitIsWhite, Bayer)); got != 128 {
+		t.Errorf("Expected 128 lit pixels, got %d", got)
+	}
+}
+
+func TestConvertDitherPolarity(t *testing.T) {
+	src := gray(16, 16, 0x40)
+	white := lit(Convert(src, LitIsWhite, Bayer))
+	black := lit(Convert(src, LitIsBlack, Bayer))
+	if white+black != 16*16 {
+		t.Errorf("Expected both polarities to complement each other, got %d and %d", white, black)
+	}
+}
+
+func TestConvertOffsetBounds(t *testing.T) {
+	src := image.NewUniform(color.White)
+	m := Convert(&subImage{src, image.Rect(5, 3, 9, 20)}, LitIsWhite, FloydSteinberg)
+	if m.Rect != image.Rect(5, 3, 9, 20) {
+		t.Errorf("Expected bounds (5,3)-(9,20), got %v", m.Rect)
+	}
+	if got := lit(m); got != 4*17 {
+		t.Errorf("Expected every pixel lit, got %d", got)
+	}
+}
+
+// subImage gives an infinite image finite bounds
+type subImage struct {
+	image.Image
+	r image.Rectangle
+}
+
+func (s *subImage) Bounds() image.Rectangle {
+	return s.r
+}
+
+func TestParseDither(t *testing.T) {
+	for _, d := range []Dither{Threshold, FloydSteinberg, Atkinson, Bayer} {
+		got, err := ParseDither(d.String())
+		if err != nil || got != d {
+			t.Errorf("Expected %s to parse back, got %v (%v)", d, got, err)
+		}
+	}
+	if _, err := ParseDither("random"); err == nil {
+		t.Error("Expected an error for an unknown dither")
+	}
+}
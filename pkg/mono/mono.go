@@ -0,0 +1,199 @@
+// Package mono provides a 1-bit image stored in the page-packed layout used by OLED controllers.
+package mono
+
+import (
+	"image"
+	"image/color"
+)
+
+var _ interface {
+	image.Image
+	Set(x, y int, c color.Color)
+} = (*Image)(nil)
+
+// Model converts any color to black or white using a 50% luminance threshold
+var Model = color.ModelFunc(model)
+
+func model(c color.Color) color.Color {
+	if isWhite(c) {
+		return color.White
+	}
+	return color.Black
+}
+
+// isWhite reports whether c is brighter than 50% gray
+func isWhite(c color.Color) bool {
+	return color.GrayModel.Convert(c).(color.Gray).Y >= 0x80
+}
+
+// Image is a 1-bit image. Pixels are grouped in pages of 8 rows; each byte
+// holds one column of a page with the top row in the least significant bit,
+// so pixel (x, y) lives in bit (y-Rect.Min.Y)%8 of
+// Pix[((y-Rect.Min.Y)/8)*Stride + (x-Rect.Min.X)].
+// A set bit is a lit pixel; Polarity decides how lit pixels map to colors.
+type Image struct {
+	Pix      []byte
+	Stride   int
+	Rect     image.Rectangle
+	Polarity Polarity
+}
+
+// New returns a new image with all pixels off
+func New(r image.Rectangle) *Image {
+	w, h := r.Dx(), r.Dy()
+	if w < 0 || h < 0 {
+		w, h = 0, 0
+	}
+	pages := (h + 7) / 8
+
+	return &Image{
+		Pix:    make([]byte, w*pages),
+		Stride: w,
+		Rect:   r,
+	}
+}
+
+// ColorModel returns the black and white color model
+func (m *Image) ColorModel() color.Model {
+	return Model
+}
+
+// Bounds returns the image bounds
+func (m *Image) Bounds() image.Rectangle {
+	return m.Rect
+}
+
+// At returns the color of the pixel according to the image polarity
+func (m *Image) At(x, y int) color.Color {
+	return m.Polarity.Color(m.GetPixel(x, y))
+}
+
+// Set lights the pixel if c maps to a lit pixel under the image polarity
+func (m *Image) Set(x, y int, c color.Color) {
+	m.SetPixel(x, y, m.Polarity.Lit(c))
+}
+
+// PixOffset returns the index of the byte holding pixel (x, y) and the bit mask within it
+func (m *Image) PixOffset(x, y int) (int, byte) {
+	x -= m.Rect.Min.X
+	y -= m.Rect.Min.Y
+	return (y/8)*m.Stride + x, 1 << (y % 8)
+}
+
+// GetPixel reports whether the pixel is on. Pixels out of bounds are off.
+func (m *Image) GetPixel(x, y int) bool {
+	if !(image.Point{x, y}.In(m.Rect)) {
+		return false
+	}
+	i, mask := m.PixOffset(x, y)
+	return m.Pix[i]&mask != 0
+}
+
+// SetPixel turns a pixel on or off. Pixels out of bounds are ignored.
+func (m *Image) SetPixel(x, y int, on bool) {
+	if !(image.Point{x, y}.In(m.Rect)) {
+		return
+	}
+	i, mask := m.PixOffset(x, y)
+	if on {
+		m.Pix[i] |= mask
+	} else {
+		m.Pix[i] &^= mask
+	}
+}
+
+// Fill turns every pixel on or off
+func (m *Image) Fill(on bool) {
+	var v byte
+	if on {
+		v = 0xFF
+	}
+	for i := range m.Pix {
+		m.Pix[i] = v
+	}
+}
+
+// Invert flips every pixel inside r
+func (m *Image) Invert(r image.Rectangle) {
+	r = r.Intersect(m.Rect)
+	if r.Empty() {
+		return
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; {
+		// Build the mask for the rows of r that fall in this page
+		i, mask := m.PixOffset(r.Min.X, y)
+		var bits byte
+		for ; y < r.Max.Y; y++ {
+			bits |= mask
+			if mask == 0x80 {
+				y++
+				break
+			}
+			mask <<= 1
+		}
+
+		for n := range r.Dx() {
+			m.Pix[i+n] ^= bits
+		}
+	}
+}
+
+// Scroll shifts the image content by dx, dy. Pixels shifted in from outside are off.
+func (m *Image) Scroll(dx, dy int) {
+	src := m.Clone()
+	m.Fill(false)
+	m.draw(src, src.Rect, src.Rect.Min.Add(image.Pt(dx, dy)))
+}
+
+// CopyRect copies the pixels inside r so that r.Min lands on dp.
+// Overlapping source and destination are handled.
+func (m *Image) CopyRect(r image.Rectangle, dp image.Point) {
+	r = r.Intersect(m.Rect)
+	if r.Empty() {
+		return
+	}
+
+	src := New(r)
+	src.draw(m, r, r.Min)
+	m.draw(src, r, dp)
+}
+
+// Clone returns a copy of the image
+func (m *Image) Clone() *Image {
+	c := &Image{
+		Pix:      make([]byte, len(m.Pix)),
+		Stride:   m.Stride,
+		Rect:     m.Rect,
+		Polarity: m.Polarity,
+	}
+	copy(c.Pix, m.Pix)
+	return c
+}
+
+// CopyFrom replaces the pixels with those of src. It returns false when the
+// bounds differ and the image is left untouched.
+func (m *Image) CopyFrom(src *Image) bool {
+	if src.Rect != m.Rect || len(src.Pix) != len(m.Pix) {
+		return false
+	}
+	copy(m.Pix, src.Pix)
+	return true
+}
+
+// draw copies the pixels of src inside sr so that sr.Min lands on dp, clipped to m
+func (m *Image) draw(src *Image, sr image.Rectangle, dp image.Point) {
+	for y := sr.Min.Y; y < sr.Max.Y; y++ {
+		ty := y - sr.Min.Y + dp.Y
+		if ty < m.Rect.Min.Y || ty >= m.Rect.Max.Y {
+			continue
+		}
+		for x := sr.Min.X; x < sr.Max.X; x++ {
+			tx := x - sr.Min.X + dp.X
+			if tx < m.Rect.Min.X || tx >= m.Rect.Max.X {
+				continue
+			}
+			m.SetPixel(tx, ty, src.GetPixel(x, y))
+		}
+	}
+}
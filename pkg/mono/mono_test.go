@@ -0,0 +1,112 @@
+package mono
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	img := New(image.Rect(0, 0, 128, 60))
+
+	if img.Stride != 128 {
+		t.Errorf("Expected stride 128, got %d", img.Stride)
+	}
+
+	if len(img.Pix) != 128*8 {
+		t.Errorf("Expected %d bytes, got %d", 128*8, len(img.Pix))
+	}
+}
+
+func TestSetGetPixel(t *testing.T) {
+	img := New(image.Rect(0, 0, 16, 16))
+
+	img.SetPixel(3, 9, true)
+	if !img.GetPixel(3, 9) {
+		t.Error("Expected pixel (3, 9) to be on")
+	}
+
+	if img.Pix[16+3] != 0x02 {
+		t.Errorf("Expected page-packed byte 0x02, got 0x%02X", img.Pix[16+3])
+	}
+
+	img.SetPixel(-1, 0, true)
+	img.SetPixel(16, 0, true)
+	if img.GetPixel(-1, 0) || img.GetPixel(16, 0) {
+		t.Error("Out of bounds pixels should be off")
+	}
+
+	img.Set(0, 0, color.White)
+	if img.At(0, 0) != color.White {
+		t.Error("Expected white pixel at (0, 0)")
+	}
+}
+
+func TestInvert(t *testing.T) {
+	img := New(image.Rect(0, 0, 16, 16))
+	img.SetPixel(5, 5, true)
+
+	img.Invert(image.Rect(2, 3, 10, 13))
+
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			inside := image.Pt(x, y).In(image.Rect(2, 3, 10, 13))
+			want := inside != (x == 5 && y == 5)
+			if img.GetPixel(x, y) != want {
+				t.Fatalf("Pixel (%d, %d): expected %v", x, y, want)
+			}
+		}
+	}
+}
+
+func TestScroll(t *testing.T) {
+	img := New(image.Rect(0, 0, 16, 16))
+	img.SetPixel(1, 1, true)
+
+	img.Scroll(3, 10)
+	if !img.GetPixel(4, 11) {
+		t.Error("Expected pixel to move to (4, 11)")
+	}
+	if img.GetPixel(1, 1) {
+		t.Error("Expected original pixel to be cleared")
+	}
+
+	img.Scroll(20, 0)
+	if img.GetPixel(4, 11) {
+		t.Error("Expected pixel to be scrolled out")
+	}
+}
+
+func TestCopyRectOverlap(t *testing.T) {
+	img := New(image.Rect(0, 0, 16, 16))
+	for x := 0; x < 4; x++ {
+		img.SetPixel(x, 0, true)
+	}
+
+	img.CopyRect(image.Rect(0, 0, 4, 1), image.Pt(2, 0))
+
+	for x := 0; x < 6; x++ {
+		if !img.GetPixel(x, 0) {
+			t.Errorf("Expected pixel (%d, 0) to be on", x)
+		}
+	}
+}
+
+func TestCloneAndCopyFrom(t *testing.T) {
+	img := New(image.Rect(0, 0, 8, 8))
+	img.SetPixel(2, 2, true)
+
+	snap := img.Clone()
+	img.Fill(false)
+
+	if !img.CopyFrom(snap) {
+		t.Fatal("CopyFrom should succeed for equal bounds")
+	}
+	if !img.GetPixel(2, 2) {
+		t.Error("Expected restored pixel (2, 2)")
+	}
+
+	if img.CopyFrom(New(image.Rect(0, 0, 4, 4))) {
+		t.Error("CopyFrom should fail for different bounds")
+	}
+}
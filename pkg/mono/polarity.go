@@ -0,0 +1,34 @@
+package mono
+
+import "image/color"
+
+// Polarity tells which color a lit pixel is represented with
+type Polarity int
+
+const (
+	// LitIsWhite maps bright colors to lit pixels, so images look the same on the panel and in previews
+	LitIsWhite Polarity = iota
+	// LitIsBlack maps dark colors to lit pixels, e.g. for artwork drawn black on white
+	LitIsBlack
+)
+
+// Lit reports whether c should light a pixel
+func (p Polarity) Lit(c color.Color) bool {
+	return isWhite(c) == (p == LitIsWhite)
+}
+
+// Color returns the color representing a lit or unlit pixel
+func (p Polarity) Color(lit bool) color.Color {
+	if lit == (p == LitIsWhite) {
+		return color.White
+	}
+	return color.Black
+}
+
+// String implements fmt.Stringer
+func (p Polarity) String() string {
+	if p == LitIsBlack {
+		return "LitIsBlack"
+	}
+	return "LitIsWhite"
+}
@@ -0,0 +1,293 @@
+// Package netpbm decodes PBM and PGM images.
+//
+// PBM bitmaps (P1 and P4) decode to *mono.Image with the mono.LitIsBlack
+// polarity, so their black pixels are the lit ones. PGM graymaps (P2 and P5)
+// decode to *image.Gray, or *image.Gray16 when the maximum value exceeds 255.
+// Importing the package registers both formats with the image package.
+package netpbm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func init() {
+	for _, magic := range []string{"P1", "P4"} {
+		image.RegisterFormat("pbm", magic, Decode, DecodeConfig)
+	}
+	for _, magic := range []string{"P2", "P5"} {
+		image.RegisterFormat("pgm", magic, Decode, DecodeConfig)
+	}
+}
+
+// maxPixels caps the size of decoded images, so a forged header cannot make
+// the decoder allocate more memory than any display could use
+const maxPixels = 1 << 26
+
+// ErrTooLarge is returned for images with more than 64 Mi pixels
+var ErrTooLarge = errors.New("netpbm: image too large")
+
+// header is the part of a netpbm file before the pixel data
+type header struct {
+	magic  string
+	width  int
+	height int
+	maxval int
+}
+
+// reader tokenizes netpbm headers and plain format pixel data
+type reader struct {
+	r *bufio.Reader
+}
+
+// skipSpace skips whitespace and comments
+func (r *reader) skipSpace() error {
+	for {
+		c, err := r.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		switch {
+		case c == '#':
+			if _, err := r.r.ReadString('\n'); err != nil {
+				return err
+			}
+		case isSpace(c):
+		default:
+			return r.r.UnreadByte()
+		}
+	}
+}
+
+// int reads a non-negative decimal number
+func (r *reader) int() (int, error) {
+	if err := r.skipSpace(); err != nil {
+		return 0, unexpected(err)
+	}
+
+	n, digits := 0, 0
+	for {
+		c, err := r.r.ReadByte()
+		if err == io.EOF && digits > 0 {
+			return n, nil
+		}
+		if err != nil {
+			return 0, unexpected(err)
+		}
+		if c < '0' || c > '9' {
+			if digits == 0 {
+				return 0, fmt.Errorf("netpbm: expected a number, got %q", c)
+			}
+			return n, r.r.UnreadByte()
+		}
+		n = n*10 + int(c-'0')
+		if n > 1<<24 {
+			return 0, errors.New("netpbm: number too large")
+		}
+		digits++
+	}
+}
+
+// bit reads a plain PBM pixel, which may not be separated by whitespace
+func (r *reader) bit() (bool, error) {
+	if err := r.skipSpace(); err != nil {
+		return false, unexpected(err)
+	}
+	c, err := r.r.ReadByte()
+	if err != nil {
+		return false, unexpected(err)
+	}
+	switch c {
+	case '0':
+		return false, nil
+	case '1':
+		return true, nil
+	}
+	return false, fmt.Errorf("netpbm: invalid PBM pixel %q", c)
+}
+
+// readHeader reads the magic number, size and maximum value
+func (r *reader) readHeader() (header, error) {
+	var h header
+	magic := make([]byte, 2)
+	if _, err := io.ReadFull(r.r, magic); err != nil {
+		return h, unexpected(err)
+	}
+	h.magic = string(magic)
+	switch h.magic {
+	case "P1", "P2", "P4", "P5":
+	default:
+		return h, errors.New("netpbm: not a PBM or PGM file")
+	}
+
+	var err error
+	if h.width, err = r.int(); err != nil {
+		return h, err
+	}
+	if h.height, err = r.int(); err != nil {
+		return h, err
+	}
+	if h.width <= 0 || h.height <= 0 {
+		return h, errors.New("netpbm: invalid image size")
+	}
+	if h.width > maxPixels/h.height {
+		return h, ErrTooLarge
+	}
+
+	h.maxval = 1
+	if h.magic == "P2" || h.magic == "P5" {
+		if h.maxval, err = r.int(); err != nil {
+			return h, err
+		}
+		if h.maxval <= 0 || h.maxval > 0xFFFF {
+			return h, errors.New("netpbm: invalid maximum value")
+		}
+	}
+
+	// A single whitespace separates the header from binary data
+	if h.magic == "P4" || h.magic == "P5" {
+		c, err := r.r.ReadByte()
+		if err != nil {
+			return h, unexpected(err)
+		}
+		if !isSpace(c) {
+			return h, errors.New("netpbm: missing whitespace after header")
+		}
+	}
+
+	return h, nil
+}
+
+// DecodeConfig returns the color model and size of a PBM or PGM image
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	h, err := (&reader{r: bufio.NewReader(r)}).readHeader()
+	if err != nil {
+		return image.Config{}, err
+	}
+
+	model := color.GrayModel
+	switch {
+	case h.magic == "P1" || h.magic == "P4":
+		model = mono.Model
+	case h.maxval > 0xFF:
+		model = color.Gray16Model
+	}
+
+	return image.Config{ColorModel: model, Width: h.width, Height: h.height}, nil
+}
+
+// Decode reads a PBM or PGM image
+func Decode(r io.Reader) (image.Image, error) {
+	rd := &reader{r: bufio.NewReader(r)}
+	h, err := rd.readHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	switch h.magic {
+	case "P1", "P4":
+		return rd.decodePBM(h)
+	}
+	return rd.decodePGM(h)
+}
+
+// decodePBM reads bitmap data, 1 meaning black
+func (r *reader) decodePBM(h header) (*mono.Image, error) {
+	img := mono.New(image.Rect(0, 0, h.width, h.height))
+	img.Polarity = mono.LitIsBlack
+
+	if h.magic == "P1" {
+		for y := 0; y < h.height; y++ {
+			for x := 0; x < h.width; x++ {
+				on, err := r.bit()
+				if err != nil {
+					return nil, err
+				}
+				img.SetPixel(x, y, on)
+			}
+		}
+		return img, nil
+	}
+
+	// Rows are packed most significant bit first and padded to a byte
+	row := make([]byte, (h.width+7)/8)
+	for y := 0; y < h.height; y++ {
+		if _, err := io.ReadFull(r.r, row); err != nil {
+			return nil, unexpected(err)
+		}
+		for x := 0; x < h.width; x++ {
+			img.SetPixel(x, y, row[x/8]&(0x80>>(x%8)) != 0)
+		}
+	}
+
+	return img, nil
+}
+
+// decodePGM reads graymap data scaled to the full range
+func (r *reader) decodePGM(h header) (image.Image, error) {
+	rect := image.Rect(0, 0, h.width, h.height)
+	var gray *image.Gray
+	var gray16 *image.Gray16
+	if h.maxval > 0xFF {
+		gray16 = image.NewGray16(rect)
+	} else {
+		gray = image.NewGray(rect)
+	}
+
+	size := 1
+	if h.maxval > 0xFF {
+		size = 2
+	}
+	buf := make([]byte, size)
+
+	for y := 0; y < h.height; y++ {
+		for x := 0; x < h.width; x++ {
+			var v int
+			if h.magic == "P2" {
+				n, err := r.int()
+				if err != nil {
+					return nil, err
+				}
+				v = n
+			} else {
+				if _, err := io.ReadFull(r.r, buf); err != nil {
+					return nil, unexpected(err)
+				}
+				v = int(buf[0])
+				if size == 2 {
+					v = v<<8 | int(buf[1])
+				}
+			}
+			v = min(v, h.maxval)
+
+			if gray16 != nil {
+				gray16.SetGray16(x, y, color.Gray16{Y: uint16(v * 0xFFFF / h.maxval)})
+			} else {
+				gray.SetGray(x, y, color.Gray{Y: uint8(v * 0xFF / h.maxval)})
+			}
+		}
+	}
+
+	if gray16 != nil {
+		return gray16, nil
+	}
+	return gray, nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// unexpected turns an EOF while reading into io.ErrUnexpectedEOF
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
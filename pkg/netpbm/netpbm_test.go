@@ -0,0 +1,166 @@
+package netpbm
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestDecodePlainPBM(t *testing.T) {
+	src := "P1\n# a comment\n4 2\n1 0 0 1\n0110\n"
+	img, err := Decode(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	m, ok := img.(*mono.Image)
+	if !ok {
+		t.Fatalf("Expected *mono.Image, got %T", img)
+	}
+	if m.Polarity != mono.LitIsBlack {
+		t.Errorf("Expected polarity %v, got %v", mono.LitIsBlack, m.Polarity)
+	}
+
+	want := [2]string{"1001", "0110"}
+	for y, row := range want {
+		for x, c := range row {
+			if got := m.GetPixel(x, y); got != (c == '1') {
+				t.Errorf("Expected pixel (%d, %d) to be %v, got %v", x, y, c == '1', got)
+			}
+		}
+	}
+
+	if c := color.GrayModel.Convert(m.At(0, 0)).(color.Gray); c.Y != 0 {
+		t.Errorf("Expected a 1 pixel to be black, got %v", c)
+	}
+}
+
+func TestDecodeRawPBM(t *testing.T) {
+	// 10 pixels per row, padded to 2 bytes
+	src := append([]byte("P4 10 2\n"), 0x80, 0x40, 0x01, 0xC0)
+	img, err := Decode(bytes.NewReader(src))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	m := img.(*mono.Image)
+	lit := map[image.Point]bool{{0, 0}: true, {9, 0}: true, {7, 1}: true, {8, 1}: true, {9, 1}: true}
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 10; x++ {
+			if got := m.GetPixel(x, y); got != lit[image.Pt(x, y)] {
+				t.Errorf("Expected pixel (%d, %d) to be %v, got %v", x, y, lit[image.Pt(x, y)], got)
+			}
+		}
+	}
+}
+
+func TestDecodePGM(t *testing.T) {
+	plain := "P2 3 1 15\n0 15 5\n"
+	raw := append([]byte("P5 3 1 15\n"), 0, 15, 5)
+
+	for name, src := range map[string][]byte{"P2": []byte(plain), "P5": raw} {
+		img, err := Decode(bytes.NewReader(src))
+		if err != nil {
+			t.Fatalf("%s: Decode failed: %v", name, err)
+		}
+		g, ok := img.(*image.Gray)
+		if !ok {
+			t.Fatalf("%s: Expected *image.Gray, got %T", name, img)
+		}
+		for x, want := range []uint8{0, 255, 85} {
+			if got := g.GrayAt(x, 0).Y; got != want {
+				t.Errorf("%s: Expected pixel %d to be %d, got %d", name, x, want, got)
+			}
+		}
+	}
+}
+
+func TestDecodePGM16(t *testing.T) {
+	src := append([]byte("P5 2 1 65535\n"), 0xFF, 0xFF, 0x80, 0x00)
+	img, err := Decode(bytes.NewReader(src))
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	g, ok := img.(*image.Gray16)
+	if !ok {
+		t.Fatalf("Expected *image.Gray16, got %T", img)
+	}
+	if got := g.Gray16At(0, 0).Y; got != 0xFFFF {
+		t.Errorf("Expected 0xFFFF, got %#x", got)
+	}
+	if got := g.Gray16At(1, 0).Y; got != 0x8000 {
+		t.Errorf("Expected 0x8000, got %#x", got)
+	}
+}
+
+func TestRegisteredFormats(t *testing.T) {
+	for _, tc := range []struct {
+		src    string
+		format string
+		model  color.Model
+	}{
+		{"P1 8 4\n" + strings.Repeat("0", 32), "pbm", mono.Model},
+		{"P2 8 4 255\n" + strings.Repeat("0 ", 32), "pgm", color.GrayModel},
+	} {
+		cfg, format, err := image.DecodeConfig(strings.NewReader(tc.src))
+		if err != nil {
+			t.Fatalf("DecodeConfig failed: %v", err)
+		}
+		if format != tc.format {
+			t.Errorf("Expected format %q, got %q", tc.format, format)
+		}
+		if cfg.Width != 8 || cfg.Height != 4 {
+			t.Errorf("Expected 8x4, got %dx%d", cfg.Width, cfg.Height)
+		}
+		if cfg.ColorModel != tc.model {
+			t.Errorf("Expected the %s color model to match", tc.format)
+		}
+
+		if _, format, err = image.Decode(strings.NewReader(tc.src)); err != nil || format != tc.format {
+			t.Errorf("Expected image.Decode to read %q, got %q (%v)", tc.format, format, err)
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, src := range []string{
+		"P3 1 1 255\n0 0 0\n",
+		"P1 0 1\n",
+		"P1 2 1\n1",
+		"P1 2 1\n12",
+		"P2 1 1 0\n0",
+		"P5 2 1 255\n\x00",
+	} {
+		if _, err := Decode(strings.NewReader(src)); err == nil {
+			t.Errorf("Expected an error for %q", src)
+		}
+	}
+
+	if _, err := Decode(strings.NewReader("P4 8 2\n\x00")); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestDecodeTooLarge(t *testing.T) {
+	for _, src := range []string{
+		"P4 16777216 16777216\n",
+		"P5 16777216 5 255\n",
+	} {
+		if _, err := Decode(strings.NewReader(src)); err != ErrTooLarge {
+			t.Errorf("Expected ErrTooLarge for %q, got %v", src, err)
+		}
+		if _, err := DecodeConfig(strings.NewReader(src)); err != ErrTooLarge {
+			t.Errorf("Expected ErrTooLarge from DecodeConfig for %q, got %v", src, err)
+		}
+	}
+
+	// The limit itself is fine, only the missing pixels are an error
+	if _, err := DecodeConfig(strings.NewReader("P4 8192 8192\n")); err != nil {
+		t.Errorf("Expected no error at the limit, got %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+// Package picture scales and adjusts arbitrary images, such as photos and
+// camera frames, to be shown on monochrome panels.
+package picture
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// Mode tells how an image is scaled into a rectangle of another aspect ratio
+type Mode int
+
+const (
+	// Fit scales the whole image to fit, leaving letterbox bars
+	Fit Mode = iota
+	// Fill scales the image to cover the rectangle, cropping the overflow
+	Fill
+	// Stretch scales each axis on its own, distorting the image
+	Stretch
+)
+
+var modeNames = [...]string{
+	Fit:     "fit",
+	Fill:    "fill",
+	Stretch: "stretch",
+}
+
+// String implements fmt.Stringer
+func (m Mode) String() string {
+	if m >= 0 && int(m) < len(modeNames) {
+		return modeNames[m]
+	}
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+// ParseMode returns the Mode named by s, as returned by String
+func ParseMode(s string) (Mode, error) {
+	for m, name := range modeNames {
+		if s == name {
+			return Mode(m), nil
+		}
+	}
+	return Fit, fmt.Errorf("picture: unknown mode %q", s)
+}
+
+// Filter selects how pixels are resampled
+type Filter int
+
+const (
+	// Nearest picks the closest source pixel, keeping hard edges, e.g. for
+	// pixel art
+	Nearest Filter = iota
+	// Bilinear interpolates the four closest source pixels, smooth when
+	// enlarging
+	Bilinear
+	// Box averages every source pixel under a destination pixel, the best
+	// choice to shrink photos without aliasing
+	Box
+)
+
+var filterNames = [...]string{
+	Nearest:  "nearest",
+	Bilinear: "bilinear",
+	Box:      "box",
+}
+
+// String implements fmt.Stringer
+func (f Filter) String() string {
+	if f >= 0 && int(f) < len(filterNames) {
+		return filterNames[f]
+	}
+	return fmt.Sprintf("Filter(%d)", int(f))
+}
+
+// ParseFilter returns the Filter named by s, as returned by String
+func ParseFilter(s string) (Filter, error) {
+	for f, name := range filterNames {
+		if s == name {
+			return Filter(f), nil
+		}
+	}
+	return Nearest, fmt.Errorf("picture: unknown filter %q", s)
+}
+
+// Place returns where an image with bounds src lands inside dst and which
+// part of it is shown. With Fit the image is centered and dr may be smaller
+// than dst; with Fill the image is centered and sr may be a crop of src.
+func Place(src, dst image.Rectangle, m Mode) (dr, sr image.Rectangle) {
+	dr, sr = dst, src
+	sw, sh := src.Dx(), src.Dy()
+	dw, dh := dst.Dx(), dst.Dy()
+	if sw <= 0 || sh <= 0 || dw <= 0 || dh <= 0 {
+		return image.Rectangle{}, image.Rectangle{}
+	}
+
+	switch m {
+	case Fit:
+		// Compare sw/sh with dw/dh without rounding
+		if sw*dh > dw*sh {
+			h := max(1, (sh*dw+sw/2)/sw)
+			dr = image.Rect(dst.Min.X, dst.Min.Y+(dh-h)/2, dst.Max.X, dst.Min.Y+(dh-h)/2+h)
+		} else {
+			w := max(1, (sw*dh+sh/2)/sh)
+			dr = image.Rect(dst.Min.X+(dw-w)/2, dst.Min.Y, dst.Min.X+(dw-w)/2+w, dst.Max.Y)
+		}
+	case Fill:
+		if sw*dh > dw*sh {
+			w := max(1, (dw*sh+dh/2)/dh)
+			sr = image.Rect(src.Min.X+(sw-w)/2, src.Min.Y, src.Min.X+(sw-w)/2+w, src.Max.Y)
+		} else {
+			h := max(1, (dh*sw+dw/2)/dw)
+			sr = image.Rect(src.Min.X, src.Min.Y+(sh-h)/2, src.Max.X, src.Min.Y+(sh-h)/2+h)
+		}
+	}
+	return dr, sr
+}
+
+// Resize returns the sr part of src scaled to size as a gray image with its
+// origin at (0, 0)
+func Resize(src image.Image, sr image.Rectangle, size image.Point, f Filter) *image.Gray {
+	dst := image.NewGray(image.Rectangle{Max: size})
+	sr = sr.Intersect(src.Bounds())
+	if sr.Empty() || size.X <= 0 || size.Y <= 0 {
+		return dst
+	}
+
+	// Luminance of the source on a 0-1 scale
+	sw, sh := sr.Dx(), sr.Dy()
+	lum := make([]float64, sw*sh)
+	for y := 0; y < sh; y++ {
+		for x := 0; x < sw; x++ {
+			g := color.Gray16Model.Convert(src.At(sr.Min.X+x, sr.Min.Y+y)).(color.Gray16)
+			lum[y*sw+x] = float64(g.Y) / 0xFFFF
+		}
+	}
+	at := func(x, y int) float64 {
+		x = max(0, min(x, sw-1))
+		y = max(0, min(y, sh-1))
+		return lum[y*sw+x]
+	}
+
+	// Source pixels per destination pixel
+	kx := float64(sw) / float64(size.X)
+	ky := float64(sh) / float64(size.Y)
+
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			var v float64
+			switch f {
+			case Bilinear:
+				fx := (float64(x)+0.5)*kx - 0.5
+				fy := (float64(y)+0.5)*ky - 0.5
+				x0, y0 := int(math.Floor(fx)), int(math.Floor(fy))
+				tx, ty := fx-float64(x0), fy-float64(y0)
+				top := at(x0, y0)*(1-tx) + at(x0+1, y0)*tx
+				bottom := at(x0, y0+1)*(1-tx) + at(x0+1, y0+1)*tx
+				v = top*(1-ty) + bottom*ty
+			case Box:
+				v = boxAverage(at, float64(x)*kx, float64(y)*ky, kx, ky)
+			default:
+				v = at(int((float64(x)+0.5)*kx), int((float64(y)+0.5)*ky))
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(math.Round(v * 0xFF))
+		}
+	}
+	return dst
+}
+
+// boxAverage returns the mean of the source area from x0, y0 spanning w by h
+// pixels, weighting partly covered pixels by their coverage
+func boxAverage(at func(x, y int) float64, x0, y0, w, h float64) float64 {
+	x1, y1 := x0+w, y0+h
+	var sum, area float64
+	for y := int(math.Floor(y0)); float64(y) < y1; y++ {
+		cy := math.Min(y1, float64(y+1)) - math.Max(y0, float64(y))
+		for x := int(math.Floor(x0)); float64(x) < x1; x++ {
+			cx := math.Min(x1, float64(x+1)) - math.Max(x0, float64(x))
+			sum += at(x, y) * cx * cy
+			area += cx * cy
+		}
+	}
+	if area == 0 {
+		return at(int(x0), int(y0))
+	}
+	return sum / area
+}
+
+// Adjustment changes the tones of an image before it is dithered. The zero
+// value leaves them unchanged.
+type Adjustment struct {
+	// Brightness is added to every level, from -1 for black to 1 for white
+	Brightness float64
+	// Contrast stretches levels away from middle gray when above 0 and
+	// flattens them when below, -1 making the image uniformly gray
+	Contrast float64
+	// Gamma raises levels to the power 1/Gamma, values above 1 lightening
+	// the midtones. Zero means 1.
+	Gamma float64
+}
+
+// Apply adjusts the pixels of img in place
+func (a Adjustment) Apply(img *image.Gray) {
+	if a == (Adjustment{}) {
+		return
+	}
+
+	// Every input level maps to one output level
+	var lut [256]uint8
+	for i := range lut {
+		v := float64(i) / 0xFF
+		v = (v-0.5)*(1+a.Contrast) + 0.5 + a.Brightness
+		v = max(0, min(v, 1))
+		if a.Gamma > 0 && a.Gamma != 1 {
+			v = math.Pow(v, 1/a.Gamma)
+		}
+		lut[i] = uint8(math.Round(v * 0xFF))
+	}
+
+	r := img.Rect
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := img.Pix[(y-r.Min.Y)*img.Stride:]
+		for x := 0; x < r.Dx(); x++ {
+			row[x] = lut[row[x]]
+		}
+	}
+}
+
+// Options controls how an image is converted for a panel
+type Options struct {
+	Mode   Mode
+	Filter Filter
+	Adjustment
+
+	// Background lights the letterbox bars left by Fit
+	Background bool
+
+	// Polarity must match the display polarity. The zero value is
+	// mono.LitIsWhite.
+	Polarity mono.Polarity
+	// Dither selects how gray levels are turned into pixels
+	Dither mono.Dither
+}
+
+// Convert scales src into r, adjusts its tones and dithers it. The result
+// has the bounds r, so it can be drawn with
+//
+//	dev.Draw(m.Rect, m, m.Rect.Min)
+//
+// Nil options fit the image with the nearest filter and threshold it.
+func Convert(src image.Image, r image.Rectangle, opts *Options) *mono.Image {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	dr, sr := Place(src.Bounds(), r, opts.Mode)
+	gray := Resize(src, sr, dr.Size(), opts.Filter)
+	opts.Adjustment.Apply(gray)
+	// Dither in place so ordered patterns line up with the screen
+	gray.Rect = gray.Rect.Add(dr.Min)
+
+	m := mono.New(r)
+	m.Polarity = opts.Polarity
+	m.Fill(opts.Background)
+	if !dr.Empty() {
+		converted := mono.Convert(gray, opts.Polarity, opts.Dither)
+		for y := dr.Min.Y; y < dr.Max.Y; y++ {
+			for x := dr.Min.X; x < dr.Max.X; x++ {
+				m.SetPixel(x, y, converted.GetPixel(x, y))
+			}
+		}
+	}
+	return m
+}
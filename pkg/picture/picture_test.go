@@ -0,0 +1,140 @@
+package picture
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// gradient returns a w by h image getting lighter to the right
+func gradient(w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(x * 0xFF / max(w-1, 1))})
+		}
+	}
+	return img
+}
+
+func TestPlace(t *testing.T) {
+	dst := image.Rect(0, 0, 128, 64)
+	for _, tc := range []struct {
+		src    image.Rectangle
+		mode   Mode
+		dr, sr image.Rectangle
+	}{
+		// 4:3 photo, pillarboxed or cropped top and bottom
+		{image.Rect(0, 0, 640, 480), Fit, image.Rect(21, 0, 106, 64), image.Rect(0, 0, 640, 480)},
+		{image.Rect(0, 0, 640, 480), Fill, dst, image.Rect(0, 80, 640, 400)},
+		// Wide banner, letterboxed or cropped left and right
+		{image.Rect(10, 10, 410, 110), Fit, image.Rect(0, 0, 128, 32).Add(image.Pt(0, 16)), image.Rect(10, 10, 410, 110)},
+		{image.Rect(10, 10, 410, 110), Fill, dst, image.Rect(110, 10, 310, 110)},
+		{image.Rect(0, 0, 640, 480), Stretch, dst, image.Rect(0, 0, 640, 480)},
+		{image.Rect(0, 0, 256, 128), Fit, dst, image.Rect(0, 0, 256, 128)},
+	} {
+		dr, sr := Place(tc.src, dst, tc.mode)
+		if dr != tc.dr || sr != tc.sr {
+			t.Errorf("%v %s: Expected %v from %v, got %v from %v", tc.src, tc.mode, tc.dr, tc.sr, dr, sr)
+		}
+	}
+	if dr, _ := Place(image.Rectangle{}, dst, Fit); !dr.Empty() {
+		t.Errorf("Expected an empty placement, got %v", dr)
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := gradient(256, 4)
+	for _, f := range []Filter{Nearest, Bilinear, Box} {
+		img := Resize(src, src.Rect, image.Pt(16, 2), f)
+		if img.Rect != image.Rect(0, 0, 16, 2) {
+			t.Fatalf("%s: Expected 16x2, got %v", f, img.Rect)
+		}
+		// The gradient survives scaling
+		for x := 1; x < 16; x++ {
+			if img.GrayAt(x, 0).Y <= img.GrayAt(x-1, 0).Y {
+				t.Errorf("%s: Expected levels to rise at %d", f, x)
+			}
+		}
+	}
+
+	// Box averages a checkerboard to middle gray, nearest keeps one phase
+	checker := image.NewGray(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if (x+y)%2 == 0 {
+				checker.SetGray(x, y, color.Gray{Y: 0xFF})
+			}
+		}
+	}
+	if v := Resize(checker, checker.Rect, image.Pt(2, 2), Box).GrayAt(0, 0).Y; v < 0x7E || v > 0x81 {
+		t.Errorf("Expected box to average to gray, got %d", v)
+	}
+	if v := Resize(checker, checker.Rect, image.Pt(2, 2), Nearest).GrayAt(0, 0).Y; v != 0 && v != 0xFF {
+		t.Errorf("Expected nearest to pick a pixel, got %d", v)
+	}
+
+	// Enlarging with bilinear interpolates between the two pixels
+	pair := gradient(2, 1)
+	if v := Resize(pair, pair.Rect, image.Pt(4, 1), Bilinear).GrayAt(1, 0).Y; v < 0x30 || v > 0x50 {
+		t.Errorf("Expected an interpolated level, got %d", v)
+	}
+}
+
+func TestAdjustment(t *testing.T) {
+	for _, tc := range []struct {
+		a    Adjustment
+		in   uint8
+		want uint8
+	}{
+		{Adjustment{}, 100, 100},
+		{Adjustment{Brightness: 0.2}, 100, 151},
+		{Adjustment{Brightness: -1}, 200, 0},
+		{Adjustment{Contrast: 1}, 0x40, 0x01},
+		{Adjustment{Contrast: -1}, 0x10, 0x80},
+		{Adjustment{Gamma: 2}, 64, 128},
+	} {
+		img := image.NewGray(image.Rect(0, 0, 1, 1))
+		img.Pix[0] = tc.in
+		tc.a.Apply(img)
+		if d := int(img.Pix[0]) - int(tc.want); d < -1 || d > 1 {
+			t.Errorf("%+v: Expected %d to become %d, got %d", tc.a, tc.in, tc.want, img.Pix[0])
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	r := image.Rect(0, 0, 128, 64)
+	src := gradient(64, 64)
+
+	m := Convert(src, r, &Options{Filter: Box, Dither: mono.Bayer, Background: true})
+	if m.Rect != r {
+		t.Fatalf("Expected bounds %v, got %v", r, m.Rect)
+	}
+	// Letterbox bars are lit, the dark end of the gradient is not
+	if !m.GetPixel(0, 0) || !m.GetPixel(127, 63) || m.GetPixel(32, 10) {
+		t.Error("Expected lit bars around a dark left edge")
+	}
+	// Dithering spreads the middle of the gradient
+	lit := 0
+	for y := 0; y < 64; y++ {
+		if m.GetPixel(64, y) {
+			lit++
+		}
+	}
+	if lit < 24 || lit > 40 {
+		t.Errorf("Expected about half the middle column lit, got %d", lit)
+	}
+
+	// Nil options threshold a fitted image, polarity flips the result
+	m = Convert(&image.Gray{Pix: []uint8{0xFF}, Stride: 1, Rect: image.Rect(0, 0, 1, 1)}, r, nil)
+	if !m.GetPixel(64, 32) || m.GetPixel(0, 0) {
+		t.Error("Expected a lit square between dark bars")
+	}
+	m = Convert(&image.Gray{Pix: []uint8{0xFF}, Stride: 1, Rect: image.Rect(0, 0, 1, 1)}, r, &Options{Polarity: mono.LitIsBlack})
+	if m.GetPixel(64, 32) || m.Polarity != mono.LitIsBlack {
+		t.Error("Expected white to stay dark under LitIsBlack")
+	}
+}
@@ -0,0 +1,298 @@
+// Package player plays animations, such as animated GIFs, on monochrome
+// displays.
+package player
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/danielgatis/go-sh1106/pkg/joystick"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+	"github.com/danielgatis/go-sh1106/pkg/picture"
+	"github.com/danielgatis/go-sh1106/pkg/sprite"
+)
+
+// ErrPlaying is returned by Play when the player is already playing
+var ErrPlaying = errors.New("player: already playing")
+
+// DefaultDelay replaces GIF frame delays of 10ms or less, like browsers do,
+// since such files expect to be slowed down
+const DefaultDelay = 100 * time.Millisecond
+
+// Frame is one pre-converted image of an animation
+type Frame struct {
+	// Image has its top-left corner at (0, 0)
+	Image *mono.Image
+	// Delay is how long the frame stays on screen
+	Delay time.Duration
+}
+
+// Animation is a sequence of frames ready to be shown
+type Animation struct {
+	Frames []Frame
+	// Loops is how many times the animation plays, 0 meaning forever
+	Loops int
+}
+
+// Duration returns the length of one loop
+func (a *Animation) Duration() time.Duration {
+	var d time.Duration
+	for _, f := range a.Frames {
+		d += f.Delay
+	}
+	return d
+}
+
+// DecodeGIF reads an animated GIF and converts every frame to fit r, e.g.
+// the display bounds, as picture.Convert does with opts. Frames are composed
+// following the GIF disposal methods, so each one is a full picture.
+func DecodeGIF(rd io.Reader, r image.Rectangle, opts *picture.Options) (*Animation, error) {
+	g, err := gif.DecodeAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	if len(g.Image) == 0 {
+		return nil, errors.New("player: GIF has no frames")
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		for _, frame := range g.Image {
+			bounds = bounds.Union(frame.Bounds())
+		}
+	}
+
+	a := &Animation{}
+	switch {
+	case g.LoopCount == -1:
+		a.Loops = 1
+	case g.LoopCount > 0:
+		// The count tells how many times the animation repeats
+		a.Loops = g.LoopCount + 1
+	}
+
+	canvas := image.NewRGBA(bounds)
+	for i, frame := range g.Image {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		m := picture.Convert(canvas, r, opts)
+		m.Rect = m.Rect.Sub(r.Min)
+		delay := DefaultDelay
+		if i < len(g.Delay) && g.Delay[i] > 1 {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+		a.Frames = append(a.Frames, Frame{Image: m, Delay: delay})
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return a, nil
+}
+
+// Target is what animations are played on, such as *display.SH1106
+type Target interface {
+	sprite.Canvas
+	Update() error
+}
+
+// Player shows an animation on a target. Play runs the animation while
+// Pause, Resume and Stop control it from other goroutines, e.g. joystick
+// callbacks.
+type Player struct {
+	target Target
+	anim   *Animation
+	at     image.Point
+
+	mu      sync.Mutex
+	playing bool
+	paused  bool
+	frame   int
+	stop    chan struct{}
+	wake    chan struct{}
+}
+
+// New returns a player showing a with its top-left corner at p
+func New(target Target, a *Animation, p image.Point) *Player {
+	return &Player{
+		target: target,
+		anim:   a,
+		at:     p,
+	}
+}
+
+// Play shows the animation, honoring the frame delays, until its loops are
+// done or Stop is called. It blocks and returns the first update error.
+func (p *Player) Play() error {
+	p.mu.Lock()
+	if p.playing {
+		p.mu.Unlock()
+		return ErrPlaying
+	}
+	p.playing = true
+	p.stop = make(chan struct{})
+	p.wake = make(chan struct{}, 1)
+	stop, wake := p.stop, p.wake
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.playing = false
+		p.mu.Unlock()
+	}()
+
+	if len(p.anim.Frames) == 0 {
+		return nil
+	}
+
+	for loop := 0; p.anim.Loops == 0 || loop < p.anim.Loops; loop++ {
+		for i, f := range p.anim.Frames {
+			p.mu.Lock()
+			p.frame = i
+			p.mu.Unlock()
+
+			sprite.Blit(p.target, p.at, sprite.Frame{Bitmap: f.Image})
+			if err := p.target.Update(); err != nil {
+				return err
+			}
+			if !p.wait(f.Delay, stop, wake) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// wait sleeps for d of unpaused time and reports false if stopped
+func (p *Player) wait(d time.Duration, stop, wake chan struct{}) bool {
+	for {
+		p.mu.Lock()
+		paused := p.paused
+		p.mu.Unlock()
+
+		if paused {
+			select {
+			case <-stop:
+				return false
+			case <-wake:
+			}
+			continue
+		}
+
+		start := time.Now()
+		timer := time.NewTimer(d)
+		select {
+		case <-stop:
+			timer.Stop()
+			return false
+		case <-timer.C:
+			return true
+		case <-wake:
+			// Paused midway, the rest of the delay runs after Resume
+			timer.Stop()
+			d -= time.Since(start)
+			if d <= 0 {
+				return true
+			}
+		}
+	}
+}
+
+// signal wakes up a waiting Play
+func (p *Player) signal() {
+	if p.wake == nil {
+		return
+	}
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pause freezes the animation on the current frame
+func (p *Player) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+	p.signal()
+}
+
+// Resume continues a paused animation
+func (p *Player) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = false
+	p.signal()
+}
+
+// Toggle pauses a running animation or resumes a paused one
+func (p *Player) Toggle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = !p.paused
+	p.signal()
+}
+
+// Stop ends Play, leaving the current frame on screen
+func (p *Player) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.playing {
+		select {
+		case <-p.stop:
+		default:
+			close(p.stop)
+		}
+	}
+}
+
+// Paused reports whether the animation is paused
+func (p *Player) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// Playing reports whether Play is running
+func (p *Player) Playing() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.playing
+}
+
+// Frame returns the index of the frame on screen
+func (p *Player) Frame() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.frame
+}
+
+// Bind lets button 1 toggle pause and button 3 stop the player. The returned
+// function removes the callbacks.
+func (p *Player) Bind(j *joystick.Joystick) joystick.RemoveCallbackFunc {
+	removeToggle := j.OnClickButton1(p.Toggle)
+	removeStop := j.OnClickButton3(p.Stop)
+	return func() {
+		removeToggle()
+		removeStop()
+	}
+}
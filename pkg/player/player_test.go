@@ -0,0 +1,195 @@
+package player
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+	"github.com/danielgatis/go-sh1106/pkg/picture"
+)
+
+// screen records the frames shown on a mono canvas
+type screen struct {
+	*mono.Image
+	mu      sync.Mutex
+	updates int
+	fail    error
+}
+
+func (s *screen) Update() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.updates++
+	return s.fail
+}
+
+func (s *screen) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.updates
+}
+
+// frames returns n 8x8 frames with pixel (i, 0) lit in frame i
+func frames(n int, delay time.Duration) []Frame {
+	var out []Frame
+	for i := 0; i < n; i++ {
+		m := mono.New(image.Rect(0, 0, 8, 8))
+		m.SetPixel(i, 0, true)
+		out = append(out, Frame{Image: m, Delay: delay})
+	}
+	return out
+}
+
+func TestPlayLoops(t *testing.T) {
+	s := &screen{Image: mono.New(image.Rect(0, 0, 16, 16))}
+	a := &Animation{Frames: frames(3, time.Millisecond), Loops: 2}
+	p := New(s, a, image.Pt(4, 4))
+
+	if err := p.Play(); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if s.count() != 6 {
+		t.Errorf("Expected 6 updates, got %d", s.count())
+	}
+	// The last frame stays on screen at the player position
+	if !s.GetPixel(6, 4) || s.GetPixel(4, 4) || p.Frame() != 2 {
+		t.Error("Expected the last frame on screen")
+	}
+	if a.Duration() != 3*time.Millisecond {
+		t.Errorf("Expected 3ms per loop, got %v", a.Duration())
+	}
+}
+
+func TestPlayError(t *testing.T) {
+	fail := errors.New("bus error")
+	s := &screen{Image: mono.New(image.Rect(0, 0, 8, 8)), fail: fail}
+	p := New(s, &Animation{Frames: frames(2, time.Millisecond)}, image.Point{})
+	if err := p.Play(); !errors.Is(err, fail) {
+		t.Errorf("Expected the update error, got %v", err)
+	}
+}
+
+func TestPauseResumeStop(t *testing.T) {
+	s := &screen{Image: mono.New(image.Rect(0, 0, 8, 8))}
+	p := New(s, &Animation{Frames: frames(2, 5*time.Millisecond)}, image.Point{})
+
+	done := make(chan error)
+	go func() { done <- p.Play() }()
+
+	deadline := time.Now().Add(time.Second)
+	for s.count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if !p.Playing() {
+		t.Fatal("Expected the player to be playing")
+	}
+	if err := p.Play(); !errors.Is(err, ErrPlaying) {
+		t.Errorf("Expected ErrPlaying, got %v", err)
+	}
+
+	p.Pause()
+	time.Sleep(10 * time.Millisecond)
+	frozen := s.count()
+	time.Sleep(30 * time.Millisecond)
+	if s.count() != frozen || !p.Paused() {
+		t.Errorf("Expected no updates while paused, got %d after %d", s.count(), frozen)
+	}
+
+	p.Toggle()
+	for s.count() == frozen && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if s.count() == frozen {
+		t.Error("Expected updates after resuming")
+	}
+
+	p.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected a clean stop, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Play to return after Stop")
+	}
+	if p.Playing() {
+		t.Error("Expected the player to be stopped")
+	}
+
+	// Stopping a paused player returns as well
+	p.Pause()
+	go func() { done <- p.Play() }()
+	for !p.Playing() {
+		time.Sleep(time.Millisecond)
+	}
+	p.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a paused Play to return after Stop")
+	}
+}
+
+func TestDecodeGIF(t *testing.T) {
+	pal := color.Palette{color.Black, color.White, color.Transparent}
+	frame := func(r image.Rectangle, c uint8) *image.Paletted {
+		m := image.NewPaletted(r, pal)
+		for i := range m.Pix {
+			m.Pix[i] = c
+		}
+		return m
+	}
+
+	// A white 8x8 background, then a black square at the top-left that is
+	// restored to the background, then a transparent frame
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			frame(image.Rect(0, 0, 8, 8), 1),
+			frame(image.Rect(0, 0, 4, 4), 0),
+			frame(image.Rect(4, 4, 8, 8), 2),
+		},
+		Delay:     []int{5, 0, 20},
+		Disposal:  []byte{gif.DisposalNone, gif.DisposalPrevious, gif.DisposalNone},
+		LoopCount: 2,
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := DecodeGIF(&buf, image.Rect(8, 8, 24, 24), &picture.Options{Mode: picture.Stretch})
+	if err != nil {
+		t.Fatalf("DecodeGIF failed: %v", err)
+	}
+	if len(a.Frames) != 3 || a.Loops != 3 {
+		t.Fatalf("Expected 3 frames and 3 loops, got %d and %d", len(a.Frames), a.Loops)
+	}
+	for i, want := range []time.Duration{50 * time.Millisecond, DefaultDelay, 200 * time.Millisecond} {
+		if a.Frames[i].Delay != want {
+			t.Errorf("Frame %d: Expected delay %v, got %v", i, want, a.Frames[i].Delay)
+		}
+	}
+
+	for i, f := range a.Frames {
+		if f.Image.Rect != image.Rect(0, 0, 16, 16) {
+			t.Fatalf("Frame %d: Expected 16x16 at the origin, got %v", i, f.Image.Rect)
+		}
+	}
+	// Frame 1 darkens the top-left, frame 2 is back to white everywhere
+	if !a.Frames[0].Image.GetPixel(0, 0) || a.Frames[1].Image.GetPixel(0, 0) || !a.Frames[1].Image.GetPixel(15, 15) {
+		t.Error("Expected the square on the second frame only")
+	}
+	if !a.Frames[2].Image.GetPixel(0, 0) || !a.Frames[2].Image.GetPixel(15, 15) {
+		t.Error("Expected the square disposed and the transparent frame to keep the background")
+	}
+
+	if _, err := DecodeGIF(bytes.NewReader([]byte("GIF89a")), image.Rect(0, 0, 8, 8), nil); err == nil {
+		t.Error("Expected an error for a truncated GIF")
+	}
+}
@@ -0,0 +1,296 @@
+package qr
+
+import "github.com/danielgatis/go-sh1106/internal/raster"
+
+// matrix is a code under construction
+type matrix struct {
+	size     int
+	dark     []bool
+	function []bool
+}
+
+func newMatrix(version int) *matrix {
+	size := version*4 + 17
+	return &matrix{size: size, dark: make([]bool, size*size), function: make([]bool, size*size)}
+}
+
+func (m *matrix) get(x, y int) bool {
+	return m.dark[y*m.size+x]
+}
+
+// setFunction sets a module that is part of a function pattern
+func (m *matrix) setFunction(x, y int, dark bool) {
+	m.dark[y*m.size+x] = dark
+	m.function[y*m.size+x] = true
+}
+
+// build lays out the function patterns and the interleaved codewords and
+// applies the mask with the lowest penalty
+func build(version int, level Level, data []byte) *Code {
+	m := newMatrix(version)
+	m.drawFunctionPatterns(version)
+	m.drawCodewords(interleave(version, level, data))
+
+	best, bestPenalty := 0, -1
+	for mask := range 8 {
+		m.applyMask(mask)
+		m.drawFormat(level, mask)
+		if p := m.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		m.applyMask(mask) // XOR again to undo
+	}
+	m.applyMask(best)
+	m.drawFormat(level, best)
+
+	return &Code{Version: version, Level: level, Mask: best, size: m.size, dark: m.dark}
+}
+
+// interleave splits the data into blocks, appends their error correction
+// codewords and interleaves everything
+func interleave(version int, level Level, data []byte) []byte {
+	blocks := numBlocks[level][version]
+	eccLen := eccPerBlock[level][version]
+	short := len(data) / blocks
+	longBlocks := len(data) % blocks
+
+	var dataBlocks, eccBlocks [][]byte
+	for i, k := 0, 0; i < blocks; i++ {
+		n := short
+		if i >= blocks-longBlocks {
+			n++
+		}
+		block := data[k : k+n]
+		k += n
+		dataBlocks = append(dataBlocks, block)
+		eccBlocks = append(eccBlocks, ecc(block, eccLen))
+	}
+
+	var out []byte
+	for i := 0; i <= short; i++ {
+		for _, b := range dataBlocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for _, b := range eccBlocks {
+			out = append(out, b[i])
+		}
+	}
+	return out
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns and
+// reserves the format areas
+func (m *matrix) drawFunctionPatterns(version int) {
+	n := m.size
+
+	// Timing patterns
+	for i := 0; i < n; i++ {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns with their separators
+	for _, c := range [][2]int{{3, 3}, {n - 4, 3}, {3, n - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x < 0 || y < 0 || x >= n || y >= n {
+					continue
+				}
+				d := max(raster.Abs(dx), raster.Abs(dy))
+				m.setFunction(x, y, d != 2 && d != 4)
+			}
+		}
+	}
+
+	// Versions 2 to 6 have a single alignment pattern
+	if version >= 2 {
+		c := n - 7
+		for dy := -2; dy <= 2; dy++ {
+			for dx := -2; dx <= 2; dx++ {
+				m.setFunction(c+dx, c+dy, max(raster.Abs(dx), raster.Abs(dy)) != 1)
+			}
+		}
+	}
+
+	// Reserve the format areas, drawn for real once the mask is known
+	m.drawFormat(L, 0)
+}
+
+// format returns the 15-bit format information: level and mask protected
+// by a BCH code and XORed with a fixed pattern
+func format(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawFormat draws both copies of the format information
+func (m *matrix) drawFormat(level Level, mask int) {
+	bits := format(level, mask)
+	bit := func(i int) bool { return bits>>i&1 != 0 }
+
+	n := m.size
+	// Around the top left finder
+	for i := 0; i <= 5; i++ {
+		m.setFunction(8, i, bit(i))
+	}
+	m.setFunction(8, 7, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(i))
+	}
+
+	// Split between the other two finders
+	for i := 0; i < 8; i++ {
+		m.setFunction(n-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, n-15+i, bit(i))
+	}
+	m.setFunction(8, n-8, true) // Always dark
+}
+
+// drawCodewords places the codewords in the two module wide zigzag going up
+// and down from the bottom right corner
+func (m *matrix) drawCodewords(data []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // Skip the vertical timing pattern
+		}
+		for vert := 0; vert < m.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = m.size - 1 - vert
+				}
+				if m.function[y*m.size+x] {
+					continue
+				}
+				// Remainder bits stay light
+				if i < len(data)*8 {
+					m.dark[y*m.size+x] = data[i>>3]>>(7-i&7)&1 != 0
+					i++
+				}
+			}
+		}
+	}
+}
+
+// maskBit reports whether mask flips module (x, y)
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	}
+	return ((x+y)%2+x*y%3)%2 == 0
+}
+
+// applyMask flips the data modules selected by mask
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if !m.function[y*m.size+x] && maskBit(mask, x, y) {
+				m.dark[y*m.size+x] = !m.dark[y*m.size+x]
+			}
+		}
+	}
+}
+
+// Penalty weights of the mask evaluation rules
+const (
+	penaltyRun     = 3
+	penaltyBlock   = 3
+	penaltyFinder  = 40
+	penaltyBalance = 10
+)
+
+// penalty scores how hard the masked symbol is to scan, lower being better
+func (m *matrix) penalty() int {
+	n := m.size
+	score := 0
+
+	// Runs of five or more modules of one color and finder-like patterns,
+	// in rows then in columns
+	for _, transpose := range []bool{false, true} {
+		at := func(i, j int) bool {
+			if transpose {
+				return m.get(i, j)
+			}
+			return m.get(j, i)
+		}
+		for i := 0; i < n; i++ {
+			run := 1
+			for j := 1; j <= n; j++ {
+				if j < n && at(i, j) == at(i, j-1) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += penaltyRun + run - 5
+				}
+				run = 1
+			}
+
+			for j := 0; j+11 <= n; j++ {
+				var w [11]bool
+				for k := range w {
+					w[k] = at(i, j+k)
+				}
+				if w == finderBefore || w == finderAfter {
+					score += penaltyFinder
+				}
+			}
+		}
+	}
+
+	// 2x2 blocks of one color
+	for y := 0; y+1 < n; y++ {
+		for x := 0; x+1 < n; x++ {
+			c := m.get(x, y)
+			if c == m.get(x+1, y) && c == m.get(x, y+1) && c == m.get(x+1, y+1) {
+				score += penaltyBlock
+			}
+		}
+	}
+
+	// Deviation of the dark proportion from 50% in 5% steps
+	dark := 0
+	for _, d := range m.dark {
+		if d {
+			dark++
+		}
+	}
+	percent := dark * 100 / (n * n)
+	score += raster.Abs(percent-50) / 5 * penaltyBalance
+
+	return score
+}
+
+// The 1:1:3:1:1 finder pattern with four light modules before or after it
+var (
+	finderBefore = [11]bool{false, false, false, false, true, false, true, true, true, false, true}
+	finderAfter  = [11]bool{true, false, true, true, true, false, true, false, false, false, false}
+)
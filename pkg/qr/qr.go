@@ -0,0 +1,185 @@
+// Package qr encodes QR codes, versions 1 to 6, and draws them onto
+// monochrome canvases.
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"strings"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+)
+
+// Level is the error correction level
+type Level int
+
+const (
+	// L recovers about 7% of the codewords
+	L Level = iota
+	// M recovers about 15% of the codewords
+	M
+	// Q recovers about 25% of the codewords
+	Q
+	// H recovers about 30% of the codewords
+	H
+)
+
+// String implements fmt.Stringer
+func (l Level) String() string {
+	if l >= L && l <= H {
+		return "LMQH"[l : l+1]
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// formatBits is the level indicator of the format information
+func (l Level) formatBits() int {
+	return [...]int{L: 1, M: 0, Q: 3, H: 2}[l]
+}
+
+// MaxVersion is the largest supported version, 41x41 modules
+const MaxVersion = 6
+
+// StandardQuietZone is the margin in modules the QR specification asks for
+const StandardQuietZone = 4
+
+var (
+	// ErrTooLong is returned when the data does not fit in MaxVersion
+	ErrTooLong = errors.New("qr: data too long")
+	// ErrTooSmall is returned when the code does not fit the target rectangle
+	ErrTooSmall = errors.New("qr: rectangle too small for the code")
+)
+
+// totalCodewords is the number of codewords of each version
+var totalCodewords = [MaxVersion + 1]int{0, 26, 44, 70, 100, 134, 172}
+
+// eccPerBlock and numBlocks are indexed by level then version
+var (
+	eccPerBlock = [4][MaxVersion + 1]int{
+		L: {0, 7, 10, 15, 20, 26, 18},
+		M: {0, 10, 16, 26, 18, 24, 16},
+		Q: {0, 13, 22, 18, 26, 18, 24},
+		H: {0, 17, 28, 22, 16, 22, 28},
+	}
+	numBlocks = [4][MaxVersion + 1]int{
+		L: {0, 1, 1, 1, 1, 1, 2},
+		M: {0, 1, 1, 1, 2, 2, 4},
+		Q: {0, 1, 1, 2, 2, 4, 4},
+		H: {0, 1, 1, 2, 4, 4, 4},
+	}
+)
+
+// dataCodewords returns the number of data codewords of a version and level
+func dataCodewords(version int, level Level) int {
+	return totalCodewords[version] - eccPerBlock[level][version]*numBlocks[level][version]
+}
+
+// Code is an encoded QR code
+type Code struct {
+	Version int
+	Level   Level
+	Mask    int
+
+	size int
+	dark []bool
+}
+
+// Encode returns the smallest code holding data at the given level. Digits
+// only and upper case alphanumeric data use the denser numeric and
+// alphanumeric modes; anything else is encoded as UTF-8 bytes.
+func Encode(data string, level Level) (*Code, error) {
+	if level < L || level > H {
+		return nil, fmt.Errorf("qr: invalid level %d", int(level))
+	}
+
+	seg := newSegment(data)
+	for version := 1; version <= MaxVersion; version++ {
+		if seg.bits() <= dataCodewords(version, level)*8 {
+			return build(version, level, seg.codewords(dataCodewords(version, level))), nil
+		}
+	}
+	return nil, ErrTooLong
+}
+
+// Size returns the width and height of the code in modules
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Dark reports whether module (x, y) is dark; modules outside the code,
+// such as the quiet zone, are light
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.size || y >= c.size {
+		return false
+	}
+	return c.dark[y*c.size+x]
+}
+
+// Options controls how a code is drawn
+type Options struct {
+	// Scale is the module size in pixels; zero picks the largest that fits
+	Scale int
+	// QuietZone is the light margin around the code in modules
+	QuietZone int
+	// Inverted lights the dark modules. By default dark modules are unlit on
+	// a lit background, like a printed code, which most scanners expect.
+	Inverted bool
+}
+
+// Draw renders the code with its quiet zone centered in r and returns the
+// area it covers. Pixels of r outside that area are left untouched.
+func (c *Code) Draw(dst draw.Image, r image.Rectangle, opts *Options) (image.Rectangle, error) {
+	if opts == nil {
+		opts = &Options{QuietZone: StandardQuietZone}
+	}
+
+	modules := c.size + 2*max(opts.QuietZone, 0)
+	scale := opts.Scale
+	if scale <= 0 {
+		scale = min(r.Dx(), r.Dy()) / modules
+	}
+	side := modules * scale
+	if scale <= 0 || side > r.Dx() || side > r.Dy() {
+		return image.Rectangle{}, ErrTooSmall
+	}
+
+	at := r.Min.Add(image.Pt((r.Dx()-side)/2, (r.Dy()-side)/2))
+	area := image.Rectangle{Min: at, Max: at.Add(image.Pt(side, side))}
+
+	light, dark := gfx.On, gfx.Off
+	if opts.Inverted {
+		light, dark = dark, light
+	}
+	gfx.Rect(dst, area, gfx.Fill(light))
+
+	quiet := max(opts.QuietZone, 0) * scale
+	origin := at.Add(image.Pt(quiet, quiet))
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.Dark(x, y) {
+				p := origin.Add(image.Pt(x*scale, y*scale))
+				gfx.Rect(dst, image.Rectangle{Min: p, Max: p.Add(image.Pt(scale, scale))}, gfx.Fill(dark))
+			}
+		}
+	}
+
+	return area, nil
+}
+
+// String renders the code as text, dark modules as '#', for debugging
+func (c *Code) String() string {
+	var b strings.Builder
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.Dark(x, y) {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,306 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// decode reads a code back the way a scanner would once the modules are
+// sampled: format information, unmasking, deinterleaving, Reed-Solomon
+// syndromes and segment parsing
+func decode(c *Code) (string, error) {
+	n := c.Size()
+	version := (n - 17) / 4
+
+	// Format information from the copy around the top left finder
+	raw := 0
+	bit := func(i int, dark bool) {
+		if dark {
+			raw |= 1 << i
+		}
+	}
+	for i := 0; i <= 5; i++ {
+		bit(i, c.Dark(8, i))
+	}
+	bit(6, c.Dark(8, 7))
+	bit(7, c.Dark(8, 8))
+	bit(8, c.Dark(7, 8))
+	for i := 9; i < 15; i++ {
+		bit(i, c.Dark(14-i, 8))
+	}
+	format := raw ^ 0x5412
+	data := format >> 10
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	if data<<10|rem != format {
+		return "", errors.New("invalid format information")
+	}
+	level := [...]Level{0: M, 1: L, 2: H, 3: Q}[data>>3]
+	mask := data & 7
+
+	// Read the zigzag, skipping function modules, and unmask
+	fm := newMatrix(version)
+	fm.drawFunctionPatterns(version)
+	var bits []bool
+	for right := n - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < n; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = n - 1 - vert
+				}
+				if !fm.function[y*n+x] {
+					bits = append(bits, c.Dark(x, y) != maskBit(mask, x, y))
+				}
+			}
+		}
+	}
+	codewords := make([]byte, totalCodewords[version])
+	for i := range codewords {
+		for k := 0; k < 8; k++ {
+			if bits[i*8+k] {
+				codewords[i] |= 0x80 >> k
+			}
+		}
+	}
+
+	// Deinterleave and check every block
+	blocks := numBlocks[level][version]
+	eccLen := eccPerBlock[level][version]
+	total := dataCodewords(version, level)
+	short := total / blocks
+	blockData := make([][]byte, blocks)
+	k := 0
+	for i := 0; i <= short; i++ {
+		for b := range blockData {
+			if i < short || b >= blocks-total%blocks {
+				blockData[b] = append(blockData[b], codewords[k])
+				k++
+			}
+		}
+	}
+	var payload []byte
+	for b := range blockData {
+		block := append([]byte{}, blockData[b]...)
+		for i := 0; i < eccLen; i++ {
+			block = append(block, codewords[total+i*blocks+b])
+		}
+		// All syndromes of a valid codeword are zero
+		for s := 0; s < eccLen; s++ {
+			var v byte
+			for _, cw := range block {
+				v = gfMul(v, gfExp[s]) ^ cw
+			}
+			if v != 0 {
+				return "", fmt.Errorf("block %d has errors", b)
+			}
+		}
+		payload = append(payload, blockData[b]...)
+	}
+
+	// Parse the single segment
+	pos := 0
+	read := func(count int) int {
+		v := 0
+		for i := 0; i < count; i++ {
+			v = v<<1 | int(payload[(pos+i)/8]>>(7-(pos+i)%8)&1)
+		}
+		pos += count
+		return v
+	}
+	var out strings.Builder
+	switch read(4) {
+	case 0x1:
+		for count := read(10); count > 0; count -= 3 {
+			digits := min(count, 3)
+			fmt.Fprintf(&out, "%0*d", digits, read([...]int{0, 4, 7, 10}[digits]))
+		}
+	case 0x2:
+		for count := read(9); count > 0; count -= 2 {
+			if count >= 2 {
+				v := read(11)
+				out.WriteByte(alphanumericChars[v/45])
+				out.WriteByte(alphanumericChars[v%45])
+			} else {
+				out.WriteByte(alphanumericChars[read(6)])
+			}
+		}
+	case 0x4:
+		for count := read(8); count > 0; count-- {
+			out.WriteByte(byte(read(8)))
+		}
+	default:
+		return "", errors.New("unknown mode")
+	}
+	return out.String(), nil
+}
+
+func TestHelloWorldCodewords(t *testing.T) {
+	// Reference codewords of "HELLO WORLD" at 1-M
+	wantData := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	wantECC := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	seg := newSegment("HELLO WORLD")
+	if seg.mode != alphanumeric {
+		t.Fatalf("Expected alphanumeric mode, got %v", seg.mode)
+	}
+	data := seg.codewords(dataCodewords(1, M))
+	if !bytes.Equal(data, wantData) {
+		t.Errorf("Expected data codewords %v, got %v", wantData, data)
+	}
+	if got := ecc(data, eccPerBlock[M][1]); !bytes.Equal(got, wantECC) {
+		t.Errorf("Expected error correction codewords %v, got %v", wantECC, got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	// Reference format strings for mask 0 and 7
+	for _, tc := range []struct {
+		level Level
+		mask  int
+		want  int
+	}{
+		{L, 0, 0b111011111000100},
+		{M, 0, 0b101010000010010},
+		{Q, 0, 0b011010101011111},
+		{H, 0, 0b001011010001001},
+		{L, 7, 0b110100101110110},
+		{H, 7, 0b000100000111011},
+	} {
+		if got := format(tc.level, tc.mask); got != tc.want {
+			t.Errorf("%s mask %d: Expected %015b, got %015b", tc.level, tc.mask, tc.want, got)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	inputs := []string{
+		"0123456789",
+		"HELLO WORLD",
+		"https://example.com/setup?id=42",
+		"DEVICE-ID: 7F3A-0001",
+		"Grüße, 設定",
+		strings.Repeat("9", 150),
+	}
+	for _, in := range inputs {
+		for _, level := range []Level{L, M, Q, H} {
+			c, err := Encode(in, level)
+			if errors.Is(err, ErrTooLong) {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Encode(%q, %s) failed: %v", in, level, err)
+			}
+			if c.Size() != c.Version*4+17 {
+				t.Errorf("Expected size %d for version %d, got %d", c.Version*4+17, c.Version, c.Size())
+			}
+			got, err := decode(c)
+			if err != nil {
+				t.Errorf("%q at %s (version %d, mask %d): %v", in, level, c.Version, c.Mask, err)
+				continue
+			}
+			if got != in {
+				t.Errorf("Expected %q at %s, decoded %q", in, level, got)
+			}
+		}
+	}
+}
+
+func TestEncodeVersions(t *testing.T) {
+	// Byte mode capacity at L for versions 1 to 6
+	for i, n := range []int{17, 32, 53, 78, 106, 134} {
+		version := i + 1
+		c, err := Encode(strings.Repeat("a", n), L)
+		if err != nil {
+			t.Fatalf("Encode of %d bytes failed: %v", n, err)
+		}
+		if c.Version != version {
+			t.Errorf("Expected %d bytes to fit version %d, got %d", n, version, c.Version)
+		}
+		if got, err := decode(c); err != nil || len(got) != n {
+			t.Errorf("Version %d did not decode: %v", version, err)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("a", 135), L); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Expected ErrTooLong, got %v", err)
+	}
+	if _, err := Encode("x", Level(9)); err == nil {
+		t.Error("Expected an error for an invalid level")
+	}
+}
+
+func TestFunctionPatterns(t *testing.T) {
+	c, err := Encode("HELLO", Q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := c.Size()
+	// Finder corners are dark, separators light, the dark module is set
+	for _, p := range []image.Point{{0, 0}, {n - 1, 0}, {0, n - 1}, {3, 3}, {8, n - 8}} {
+		if !c.Dark(p.X, p.Y) {
+			t.Errorf("Expected module %v to be dark", p)
+		}
+	}
+	for _, p := range []image.Point{{7, 7}, {n - 8, 0}, {0, n - 8}, {-1, 0}, {n, n}} {
+		if c.Dark(p.X, p.Y) {
+			t.Errorf("Expected module %v to be light", p)
+		}
+	}
+}
+
+func TestDraw(t *testing.T) {
+	c, err := Encode("HELLO WORLD", M)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	area, err := c.Draw(img, img.Rect, nil)
+	if err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+
+	// 21 modules plus 4 on each side, scaled by 2 and centered
+	if want := image.Rect(35, 3, 93, 61); area != want {
+		t.Fatalf("Expected area %v, got %v", want, area)
+	}
+	for y := 0; y < 21; y++ {
+		for x := 0; x < 21; x++ {
+			px, py := area.Min.X+(4+x)*2, area.Min.Y+(4+y)*2
+			for _, d := range []image.Point{{0, 0}, {1, 1}} {
+				if img.GetPixel(px+d.X, py+d.Y) == c.Dark(x, y) {
+					t.Fatalf("Expected module (%d, %d) to be drawn dark on light", x, y)
+				}
+			}
+		}
+	}
+	if !img.GetPixel(area.Min.X, area.Min.Y) || img.GetPixel(0, 0) {
+		t.Error("Expected a lit quiet zone and untouched pixels outside it")
+	}
+
+	// Explicit scale, no quiet zone, inverted
+	img.Fill(false)
+	area, err = c.Draw(img, img.Rect, &Options{Scale: 1, Inverted: true})
+	if err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+	if area.Dx() != 21 || !img.GetPixel(area.Min.X, area.Min.Y) {
+		t.Errorf("Expected an inverted 21 pixel code, got %v", area)
+	}
+
+	if _, err := c.Draw(img, image.Rect(0, 0, 20, 20), &Options{}); !errors.Is(err, ErrTooSmall) {
+		t.Errorf("Expected ErrTooSmall, got %v", err)
+	}
+}
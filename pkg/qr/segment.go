@@ -0,0 +1,128 @@
+package qr
+
+import "strings"
+
+// mode is the encoding of a segment
+type mode int
+
+const (
+	numeric mode = iota
+	alphanumeric
+	byteMode
+)
+
+// indicator is the 4-bit mode indicator
+func (m mode) indicator() int {
+	return [...]int{numeric: 0x1, alphanumeric: 0x2, byteMode: 0x4}[m]
+}
+
+// countBits is the length of the character count for versions 1 to 9
+func (m mode) countBits() int {
+	return [...]int{numeric: 10, alphanumeric: 9, byteMode: 8}[m]
+}
+
+// alphanumericChars is the alphanumeric mode charset, in code order
+const alphanumericChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// segment is the whole data encoded in a single mode
+type segment struct {
+	mode mode
+	data string
+}
+
+// newSegment picks the densest mode able to encode data
+func newSegment(data string) segment {
+	m := numeric
+	for _, c := range data {
+		switch {
+		case c >= '0' && c <= '9':
+		case strings.ContainsRune(alphanumericChars, c):
+			m = max(m, alphanumeric)
+		default:
+			return segment{mode: byteMode, data: data}
+		}
+	}
+	return segment{mode: m, data: data}
+}
+
+// count returns the number of characters as stored in the count field
+func (s segment) count() int {
+	return len(s.data)
+}
+
+// bits returns the length of the encoded segment including its header
+func (s segment) bits() int {
+	n := len(s.data)
+	payload := 0
+	switch s.mode {
+	case numeric:
+		payload = n/3*10 + [...]int{0, 4, 7}[n%3]
+	case alphanumeric:
+		payload = n/2*11 + n%2*6
+	default:
+		payload = n * 8
+	}
+	return 4 + s.mode.countBits() + payload
+}
+
+// codewords returns the segment padded to n data codewords. The caller
+// ensures it fits.
+func (s segment) codewords(n int) []byte {
+	var b bitBuffer
+	b.append(s.mode.indicator(), 4)
+	b.append(s.count(), s.mode.countBits())
+
+	switch s.mode {
+	case numeric:
+		for i := 0; i < len(s.data); i += 3 {
+			group := s.data[i:min(i+3, len(s.data))]
+			v := 0
+			for _, c := range group {
+				v = v*10 + int(c-'0')
+			}
+			b.append(v, [...]int{0, 4, 7, 10}[len(group)])
+		}
+	case alphanumeric:
+		for i := 0; i < len(s.data); i += 2 {
+			v := strings.IndexByte(alphanumericChars, s.data[i])
+			if i+1 < len(s.data) {
+				b.append(v*45+strings.IndexByte(alphanumericChars, s.data[i+1]), 11)
+			} else {
+				b.append(v, 6)
+			}
+		}
+	default:
+		for i := 0; i < len(s.data); i++ {
+			b.append(int(s.data[i]), 8)
+		}
+	}
+
+	// Terminator, byte alignment, then alternating pad codewords
+	capacity := n * 8
+	b.append(0, min(4, capacity-b.n))
+	b.append(0, (8-b.n%8)%8)
+	for pad := 0; b.n < capacity; pad++ {
+		b.append([2]int{0xEC, 0x11}[pad%2], 8)
+	}
+
+	return b.bytes
+}
+
+// bitBuffer is a big endian sequence of bits
+type bitBuffer struct {
+	bytes []byte
+	n     int
+}
+
+// append adds the low count bits of v, most significant first
+func (b *bitBuffer) append(v, count int) {
+	for i := count - 1; i >= 0; i-- {
+		if b.n%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if v>>i&1 != 0 {
+			b.bytes[b.n/8] |= 0x80 >> (b.n % 8)
+		}
+		b.n++
+	}
+}
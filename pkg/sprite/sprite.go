@@ -0,0 +1,222 @@
+// Package sprite provides masked monochrome sprites and fast blitting onto display buffers.
+package sprite
+
+import (
+	"errors"
+	"image"
+	"time"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// Canvas is what sprites are blitted onto, such as *mono.Image,
+// *display.SH1106 or *layer.Layer
+type Canvas interface {
+	Bounds() image.Rectangle
+	SetPixel(x, y int, on bool)
+}
+
+// Buffered is implemented by canvases exposing their page-packed buffer,
+// such as *display.SH1106. Blitting onto them works on whole bytes.
+type Buffered interface {
+	Buffer() *mono.Image
+}
+
+// Frame is one image of a sprite
+type Frame struct {
+	// Bitmap holds the lit pixels
+	Bitmap *mono.Image
+	// Mask holds the opaque pixels; pixels outside the mask are transparent.
+	// It is aligned with the bitmap by their top-left corners, so bitmap
+	// pixels beyond a smaller mask are transparent too. A nil mask makes the
+	// whole frame opaque.
+	Mask *mono.Image
+}
+
+// packed reports whether the frame can be blitted a byte at a time: the
+// bitmap starts at the origin and the mask, if any, covers it exactly
+func (f Frame) packed() bool {
+	return f.Bitmap.Rect.Min == image.Point{} && (f.Mask == nil || f.Mask.Rect == f.Bitmap.Rect)
+}
+
+// Sprite is a sequence of equally sized frames, e.g. an animation
+type Sprite struct {
+	frames []Frame
+	size   image.Point
+}
+
+// New returns a single frame sprite. Pass the bitmap as mask as well to
+// draw only its lit pixels, or nil to make it opaque.
+func New(bitmap, mask *mono.Image) (*Sprite, error) {
+	return FromStrip(bitmap, mask, bitmap.Rect.Dx())
+}
+
+// FromStrip cuts a horizontal animation strip into frames of frameWidth
+// pixels. The mask, if any, must have the same size as the strip.
+func FromStrip(strip, mask *mono.Image, frameWidth int) (*Sprite, error) {
+	r := strip.Rect
+	if frameWidth <= 0 || r.Dx() == 0 || r.Dx()%frameWidth != 0 {
+		return nil, errors.New("sprite: strip width must be a multiple of the frame width")
+	}
+	if mask != nil && mask.Rect.Size() != r.Size() {
+		return nil, errors.New("sprite: mask size does not match the bitmap")
+	}
+
+	s := &Sprite{size: image.Pt(frameWidth, r.Dy())}
+	for x := 0; x < r.Dx(); x += frameWidth {
+		src := image.Rect(x, 0, x+frameWidth, r.Dy())
+		f := Frame{Bitmap: crop(strip, src.Add(r.Min))}
+		if mask != nil {
+			f.Mask = crop(mask, src.Add(mask.Rect.Min))
+		}
+		s.frames = append(s.frames, f)
+	}
+
+	return s, nil
+}
+
+// crop copies the pixels of src inside r into a new image at the origin
+func crop(src *mono.Image, r image.Rectangle) *mono.Image {
+	dst := mono.New(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < r.Dx(); x++ {
+			dst.SetPixel(x, y, src.GetPixel(r.Min.X+x, r.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// Len returns the number of frames
+func (s *Sprite) Len() int {
+	return len(s.frames)
+}
+
+// Size returns the size of a frame
+func (s *Sprite) Size() image.Point {
+	return s.size
+}
+
+// Frame returns frame i, wrapping around the number of frames
+func (s *Sprite) Frame(i int) Frame {
+	n := len(s.frames)
+	return s.frames[((i%n)+n)%n]
+}
+
+// FrameAt returns the frame index shown after elapsed time when every frame
+// lasts perFrame, looping forever. A negative elapsed time shows the first
+// frame.
+func (s *Sprite) FrameAt(elapsed, perFrame time.Duration) int {
+	if perFrame <= 0 || elapsed < 0 {
+		return 0
+	}
+	return int(elapsed/perFrame) % len(s.frames)
+}
+
+// FlipH returns a copy of the sprite mirrored left to right
+func (s *Sprite) FlipH() *Sprite {
+	return s.transform(func(x, y int) (int, int) { return s.size.X - 1 - x, y })
+}
+
+// FlipV returns a copy of the sprite mirrored top to bottom
+func (s *Sprite) FlipV() *Sprite {
+	return s.transform(func(x, y int) (int, int) { return x, s.size.Y - 1 - y })
+}
+
+// transform returns a copy of the sprite where pixel (x, y) is read from f(x, y)
+func (s *Sprite) transform(f func(x, y int) (int, int)) *Sprite {
+	t := &Sprite{size: s.size}
+	for _, fr := range s.frames {
+		nf := Frame{Bitmap: raster.Remap(fr.Bitmap, f)}
+		if fr.Mask != nil {
+			nf.Mask = raster.Remap(fr.Mask, f)
+		}
+		t.frames = append(t.frames, nf)
+	}
+	return t
+}
+
+// Blit draws frame i with its top-left corner at p, clipped to dst
+func (s *Sprite) Blit(dst Canvas, p image.Point, i int) {
+	Blit(dst, p, s.Frame(i))
+}
+
+// Blit draws a frame with its top-left corner at p, clipped to dst.
+// Page-packed destinations are written a byte at a time when the frame
+// bitmap starts at the origin and the mask has the same bounds, as for the
+// frames of a Sprite; other frames are drawn pixel by pixel.
+func Blit(dst Canvas, p image.Point, f Frame) {
+	if f.packed() {
+		switch d := dst.(type) {
+		case *mono.Image:
+			blitPacked(d, p, f)
+			return
+		case Buffered:
+			blitPacked(d.Buffer(), p, f)
+			return
+		}
+	}
+
+	r := f.Bitmap.Rect
+	for y := 0; y < r.Dy(); y++ {
+		for x := 0; x < r.Dx(); x++ {
+			if f.Mask == nil || f.Mask.GetPixel(f.Mask.Rect.Min.X+x, f.Mask.Rect.Min.Y+y) {
+				dst.SetPixel(p.X+x, p.Y+y, f.Bitmap.GetPixel(r.Min.X+x, r.Min.Y+y))
+			}
+		}
+	}
+}
+
+// blitPacked merges the frame into a page-packed image one column byte at a time
+func blitPacked(dst *mono.Image, p image.Point, f Frame) {
+	w, h := f.Bitmap.Rect.Dx(), f.Bitmap.Rect.Dy()
+	pages := (h + 7) / 8
+	dstPages := (dst.Rect.Dy() + 7) / 8
+	// Keep the bits below the last row of the destination
+	lastValid := byte(0xFF)
+	if rows := dst.Rect.Dy() % 8; rows != 0 {
+		lastValid = byte(1<<rows) - 1
+	}
+
+	// Row offset in the destination split into whole pages and a bit shift
+	dy := p.Y - dst.Rect.Min.Y
+	pageOff := raster.FloorDiv(dy, 8)
+	shift := uint(dy - pageOff*8)
+
+	x0 := max(0, dst.Rect.Min.X-p.X)
+	x1 := min(w, dst.Rect.Max.X-p.X)
+
+	for sp := 0; sp < pages; sp++ {
+		// Ignore the bits below the last row of the frame
+		valid := byte(0xFF)
+		if rows := h - sp*8; rows < 8 {
+			valid = byte(1<<rows) - 1
+		}
+
+		for x := x0; x < x1; x++ {
+			b := f.Bitmap.Pix[sp*f.Bitmap.Stride+x]
+			m := valid
+			if f.Mask != nil {
+				m &= f.Mask.Pix[sp*f.Mask.Stride+x]
+			}
+			if m == 0 {
+				continue
+			}
+
+			col := p.X + x - dst.Rect.Min.X
+			lo := uint16(b) << shift
+			mlo := uint16(m) << shift
+			for k, dp := range [2]int{sp + pageOff, sp + pageOff + 1} {
+				mm := byte(mlo >> (8 * k))
+				if dp == dstPages-1 {
+					mm &= lastValid
+				}
+				if mm == 0 || dp < 0 || dp >= dstPages {
+					continue
+				}
+				i := dp*dst.Stride + col
+				dst.Pix[i] = dst.Pix[i]&^mm | byte(lo>>(8*k))&mm
+			}
+		}
+	}
+}
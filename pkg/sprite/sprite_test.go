@@ -0,0 +1,185 @@
+package sprite
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// slowCanvas only supports SetPixel, forcing the generic blit path
+type slowCanvas struct {
+	*mono.Image
+}
+
+func (c slowCanvas) SetPixel(x, y int, on bool) {
+	c.Image.SetPixel(x, y, on)
+}
+
+// arrow returns a 5x11 bitmap with a few lit pixels and a mask around them
+func arrow() (*mono.Image, *mono.Image) {
+	bm := mono.New(image.Rect(0, 0, 5, 11))
+	mask := mono.New(image.Rect(0, 0, 5, 11))
+	for y := 0; y < 11; y++ {
+		bm.SetPixel(2, y, true)
+		for x := 1; x <= 3; x++ {
+			mask.SetPixel(x, y, true)
+		}
+	}
+	bm.SetPixel(0, 0, true) // Outside the mask, must never be drawn
+	return bm, mask
+}
+
+func TestBlitMatchesGenericPath(t *testing.T) {
+	bm, mask := arrow()
+	s, err := New(bm, mask)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for _, p := range []image.Point{{0, 0}, {3, 5}, {-2, -3}, {30, 13}, {10, -7}} {
+		fast := mono.New(image.Rect(0, 0, 32, 20))
+		slow := slowCanvas{mono.New(image.Rect(0, 0, 32, 20))}
+		fast.Fill(true)
+		slow.Fill(true)
+
+		s.Blit(fast, p, 0)
+		s.Blit(slow, p, 0)
+
+		for y := 0; y < 20; y++ {
+			for x := 0; x < 32; x++ {
+				if fast.GetPixel(x, y) != slow.GetPixel(x, y) {
+					t.Fatalf("At %v: pixel (%d, %d) differs between blit paths", p, x, y)
+				}
+			}
+		}
+
+		// Masked out pixels keep the background, opaque dark pixels are drawn
+		if q := p.Add(image.Pt(0, 0)); q.In(fast.Rect) && !fast.GetPixel(q.X, q.Y) {
+			t.Errorf("At %v: expected pixel outside the mask to be untouched", p)
+		}
+		if q := p.Add(image.Pt(1, 1)); q.In(fast.Rect) && fast.GetPixel(q.X, q.Y) {
+			t.Errorf("At %v: expected opaque dark pixel to be drawn", p)
+		}
+	}
+}
+
+func TestFromStrip(t *testing.T) {
+	strip := mono.New(image.Rect(0, 0, 12, 4))
+	for i := 0; i < 3; i++ {
+		strip.SetPixel(i*4+i, 0, true) // Frame i lights column i
+	}
+
+	s, err := FromStrip(strip, nil, 4)
+	if err != nil {
+		t.Fatalf("FromStrip failed: %v", err)
+	}
+
+	if s.Len() != 3 || s.Size() != image.Pt(4, 4) {
+		t.Fatalf("Expected 3 frames of 4x4, got %d of %v", s.Len(), s.Size())
+	}
+
+	for i := 0; i < 3; i++ {
+		if !s.Frame(i).Bitmap.GetPixel(i, 0) {
+			t.Errorf("Frame %d: expected column %d to be lit", i, i)
+		}
+	}
+
+	if s.Frame(4).Bitmap != s.Frame(1).Bitmap {
+		t.Error("Expected frame index to wrap around")
+	}
+
+	if s.FrameAt(250_000_000, 100_000_000) != 2 {
+		t.Error("Expected frame 2 after 250ms at 100ms per frame")
+	}
+	if s.FrameAt(-250_000_000, 100_000_000) != 0 {
+		t.Error("Expected frame 0 before the animation starts")
+	}
+
+	if _, err := FromStrip(strip, nil, 5); err == nil {
+		t.Error("Expected error for a frame width not dividing the strip, got nil")
+	}
+}
+
+func TestFlip(t *testing.T) {
+	bm := mono.New(image.Rect(0, 0, 4, 3))
+	bm.SetPixel(0, 0, true)
+	s, _ := New(bm, nil)
+
+	if !s.FlipH().Frame(0).Bitmap.GetPixel(3, 0) {
+		t.Error("Expected FlipH to mirror columns")
+	}
+	if !s.FlipV().Frame(0).Bitmap.GetPixel(0, 2) {
+		t.Error("Expected FlipV to mirror rows")
+	}
+	if !s.Frame(0).Bitmap.GetPixel(0, 0) {
+		t.Error("Expected the original sprite to be unchanged")
+	}
+}
+
+func BenchmarkBlit(b *testing.B) {
+	bm := mono.New(image.Rect(0, 0, 16, 16))
+	bm.Fill(true)
+	s, _ := New(bm, bm)
+	dst := mono.New(image.Rect(0, 0, 128, 64))
+
+	for i := 0; b.Loop(); i++ {
+		s.Blit(dst, image.Pt(i%112, i%48), 0)
+	}
+}
+
+func TestBlitUnalignedFrame(t *testing.T) {
+	bm, mask := arrow()
+
+	// The same pixels at another origin, with a mask cut short at the bottom
+	moved := mono.New(image.Rect(3, 5, 8, 16))
+	short := mono.New(image.Rect(-4, 2, 1, 8))
+	for y := 0; y < 11; y++ {
+		for x := 0; x < 5; x++ {
+			moved.SetPixel(3+x, 5+y, bm.GetPixel(x, y))
+			short.SetPixel(-4+x, 2+y, mask.GetPixel(x, y))
+		}
+	}
+
+	p := image.Pt(6, 2)
+	for _, dst := range []Canvas{mono.New(image.Rect(0, 0, 16, 16)), slowCanvas{mono.New(image.Rect(0, 0, 16, 16))}} {
+		var img *mono.Image
+		switch d := dst.(type) {
+		case *mono.Image:
+			img = d
+		case slowCanvas:
+			img = d.Image
+		}
+		img.Fill(true)
+		Blit(dst, p, Frame{Bitmap: moved, Mask: short})
+
+		for y := 0; y < 11; y++ {
+			for x := 0; x < 5; x++ {
+				// Rows beyond the 6 row mask are transparent
+				want := true
+				if y < 6 && mask.GetPixel(x, y) {
+					want = bm.GetPixel(x, y)
+				}
+				if got := img.GetPixel(p.X+x, p.Y+y); got != want {
+					t.Fatalf("Expected pixel (%d, %d) of the frame to be %v, got %v", x, y, want, got)
+				}
+			}
+		}
+	}
+}
+
+func TestBlitPartialLastPage(t *testing.T) {
+	bm := mono.New(image.Rect(0, 0, 4, 8))
+	bm.Fill(true)
+	s, _ := New(bm, nil)
+
+	// The last page of a 12 row canvas only has 4 rows
+	dst := mono.New(image.Rect(0, 0, 4, 12))
+	s.Blit(dst, image.Pt(0, 6), 0)
+
+	for x := 0; x < 4; x++ {
+		if got := dst.Pix[dst.Stride+x]; got != 0x0F {
+			t.Errorf("Expected only the 4 rows of the last page set in column %d, got %#02x", x, got)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package stream reads video as a sequence of frames, e.g. piped from
+// ffmpeg, and shows it on a display at a steady frame rate.
+package stream
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"time"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+	"github.com/danielgatis/go-sh1106/pkg/netpbm"
+	"github.com/danielgatis/go-sh1106/pkg/picture"
+	"github.com/danielgatis/go-sh1106/pkg/sprite"
+)
+
+// Format is the encoding of the frames in a stream
+type Format int
+
+const (
+	// PNM frames are PBM or PGM images, plain or binary, one after another,
+	// such as the output of ffmpeg -f image2pipe -c:v pgm
+	PNM Format = iota
+	// Raw frames are page-packed bitmaps in the display RAM layout, one byte
+	// per column of 8 rows with the top row in the least significant bit
+	Raw
+)
+
+var formatNames = [...]string{
+	PNM: "pnm",
+	Raw: "raw",
+}
+
+// String implements fmt.Stringer
+func (f Format) String() string {
+	if f >= 0 && int(f) < len(formatNames) {
+		return formatNames[f]
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// ParseFormat returns the Format named by s, as returned by String
+func ParseFormat(s string) (Format, error) {
+	for f, name := range formatNames {
+		if s == name {
+			return Format(f), nil
+		}
+	}
+	return PNM, fmt.Errorf("stream: unknown format %q", s)
+}
+
+// Reader decodes frames of a fixed size from a stream
+type Reader struct {
+	r      *bufio.Reader
+	format Format
+	rect   image.Rectangle
+	opts   *picture.Options
+}
+
+// NewReader returns a reader of frames sized to fill r, e.g. the display
+// bounds. Raw frames must have exactly that size; PNM frames of other sizes
+// are scaled and dithered following opts, as picture.Convert does.
+func NewReader(rd io.Reader, format Format, r image.Rectangle, opts *picture.Options) *Reader {
+	return &Reader{
+		r:      bufio.NewReader(rd),
+		format: format,
+		rect:   image.Rectangle{Max: r.Size()},
+		opts:   opts,
+	}
+}
+
+// Next returns the next frame with its top-left corner at (0, 0). It returns
+// io.EOF when the stream ends between frames and io.ErrUnexpectedEOF when
+// it ends inside one.
+func (r *Reader) Next() (*mono.Image, error) {
+	if r.format == Raw {
+		m := mono.New(r.rect)
+		if r.opts != nil {
+			m.Polarity = r.opts.Polarity
+		}
+		if _, err := io.ReadFull(r.r, m.Pix); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+
+	img, err := r.decode()
+	if err != nil {
+		return nil, err
+	}
+	return picture.Convert(img, r.rect, r.opts), nil
+}
+
+// Skip reads past the next frame without converting it
+func (r *Reader) Skip() error {
+	if r.format == Raw {
+		n := int64(r.rect.Dx() * ((r.rect.Dy() + 7) / 8))
+		written, err := io.CopyN(io.Discard, r.r, n)
+		if err == io.EOF && written > 0 {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	_, err := r.decode()
+	return err
+}
+
+// decode reads the next PNM image, telling the end of the stream apart from
+// a truncated frame
+func (r *Reader) decode() (image.Image, error) {
+	// Plain frames may be followed by whitespace before the next magic number
+	for {
+		c, err := r.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			if err := r.r.UnreadByte(); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	// The shared buffered reader is used as is, so no bytes of the following
+	// frame are lost
+	return netpbm.Decode(r.r)
+}
+
+// Target is what frames are shown on, such as *display.SH1106
+type Target interface {
+	sprite.Canvas
+	Update() error
+}
+
+// Stats counts the frames of a stream
+type Stats struct {
+	Shown   int
+	Dropped int
+}
+
+// Play shows the frames of r on target at fps frames per second until the
+// stream ends, returning nil at a clean end. The clock starts with the first
+// frame and restarts whenever the input is late, so a slow source only
+// delays the video, while a slow target drops the frames it has fallen
+// behind on instead of slowing the video down. An fps of 0 shows every frame
+// as soon as it arrives.
+func Play(target Target, r *Reader, fps float64) (Stats, error) {
+	var stats Stats
+	var period time.Duration
+	if fps > 0 {
+		period = time.Duration(float64(time.Second) / fps)
+	}
+
+	var start time.Time
+	for i := 0; ; i++ {
+		due := start.Add(time.Duration(i) * period)
+		if period > 0 && !start.IsZero() && time.Since(due) >= period {
+			// The target fell behind and the frame is over before it could be shown
+			if err := r.Skip(); err != nil {
+				return stats, end(err)
+			}
+			stats.Dropped++
+			continue
+		}
+		early := time.Now().Before(due)
+
+		m, err := r.Next()
+		if err != nil {
+			return stats, end(err)
+		}
+		if period > 0 {
+			now := time.Now()
+			if start.IsZero() || early && now.After(due) {
+				// Waiting for the input made the frame late, count from it
+				start, i = now, 0
+			} else if wait := due.Sub(now); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+
+		sprite.Blit(target, target.Bounds().Min, sprite.Frame{Bitmap: m})
+		if err := target.Update(); err != nil {
+			return stats, err
+		}
+		stats.Shown++
+	}
+}
+
+// end turns the end of the stream into a nil error
+func end(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// screen counts the frames shown on a mono canvas
+type screen struct {
+	*mono.Image
+	updates int
+	delay   time.Duration
+}
+
+func (s *screen) Update() error {
+	s.updates++
+	time.Sleep(s.delay)
+	return nil
+}
+
+// pgm returns an 8x8 binary graymap with column x white
+func pgm(x int) []byte {
+	pix := make([]byte, 64)
+	for y := 0; y < 8; y++ {
+		pix[y*8+x] = 0xFF
+	}
+	return append([]byte("P5\n8 8\n255\n"), pix...)
+}
+
+func TestReaderPNM(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(pgm(1))
+	// A plain bitmap with the top-left pixel black, then trailing newlines
+	buf.WriteString("P1\n8 8\n" + strings.Repeat("0 ", 63) + "1\n\n")
+	buf.Write(pgm(6))
+
+	r := NewReader(&buf, PNM, image.Rect(0, 0, 8, 8), nil)
+	m, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if m.Rect != image.Rect(0, 0, 8, 8) || !m.GetPixel(1, 5) || m.GetPixel(2, 5) {
+		t.Error("Expected column 1 lit on the first frame")
+	}
+
+	// Black bitmap pixels stay dark, the rest is lit
+	if m, err = r.Next(); err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if m.GetPixel(7, 7) || !m.GetPixel(0, 0) {
+		t.Error("Expected the black pixel of the bitmap to be dark")
+	}
+
+	if err := r.Skip(); err != nil {
+		t.Fatalf("Skip failed: %v", err)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestReaderScales(t *testing.T) {
+	// A 16x16 frame is fitted into an 8x8 display
+	frame := append([]byte("P5\n16 16\n255\n"), bytes.Repeat([]byte{0xFF}, 256)...)
+	m, err := NewReader(bytes.NewReader(frame), PNM, image.Rect(0, 0, 8, 8), nil).Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if m.Rect.Dx() != 8 || !m.GetPixel(7, 7) {
+		t.Errorf("Expected a lit 8x8 frame, got %v", m.Rect)
+	}
+}
+
+func TestReaderRaw(t *testing.T) {
+	// Two 4x16 frames, two pages each
+	data := []byte{0x01, 0, 0, 0, 0, 0, 0, 0x80, 0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0, 0x01}
+	r := NewReader(bytes.NewReader(data), Raw, image.Rect(0, 0, 4, 16), nil)
+
+	m, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if !m.GetPixel(0, 0) || !m.GetPixel(3, 15) || m.GetPixel(1, 0) {
+		t.Error("Expected the page-packed pixels to be lit")
+	}
+	if err := r.Skip(); err != nil {
+		t.Fatalf("Skip failed: %v", err)
+	}
+	if err := r.Skip(); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF for a partial frame, got %v", err)
+	}
+}
+
+func TestPlay(t *testing.T) {
+	var buf bytes.Buffer
+	for i := 0; i < 8; i++ {
+		buf.Write(pgm(i))
+	}
+	s := &screen{Image: mono.New(image.Rect(0, 0, 16, 8))}
+
+	stats, err := Play(s, NewReader(&buf, PNM, image.Rect(0, 0, 8, 8), nil), 0)
+	if err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if stats.Shown != 8 || stats.Dropped != 0 || s.updates != 8 {
+		t.Errorf("Expected 8 frames shown, got %+v", stats)
+	}
+	if !s.GetPixel(7, 0) || s.GetPixel(6, 0) {
+		t.Error("Expected the last frame on screen")
+	}
+}
+
+func TestPlayDrops(t *testing.T) {
+	var buf bytes.Buffer
+	for i := 0; i < 20; i++ {
+		buf.Write(pgm(i % 8))
+	}
+	// The target takes 3 frame periods per update
+	s := &screen{Image: mono.New(image.Rect(0, 0, 8, 8)), delay: 15 * time.Millisecond}
+
+	start := time.Now()
+	stats, err := Play(s, NewReader(&buf, PNM, image.Rect(0, 0, 8, 8), nil), 200)
+	if err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	if stats.Shown+stats.Dropped != 20 || stats.Dropped < 8 {
+		t.Errorf("Expected most frames dropped, got %+v", stats)
+	}
+	// Dropping keeps the video close to its 100ms length
+	if d := time.Since(start); d > 250*time.Millisecond {
+		t.Errorf("Expected the video to keep its pace, took %v", d)
+	}
+}
+
+// paced delivers one frame per read, the first after delay and the others
+// period apart, like a live source
+type paced struct {
+	frames [][]byte
+	delay  time.Duration
+	period time.Duration
+}
+
+func (p *paced) Read(b []byte) (int, error) {
+	if len(p.frames) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(p.delay)
+	p.delay = p.period
+	n := copy(b, p.frames[0])
+	if p.frames[0] = p.frames[0][n:]; len(p.frames[0]) == 0 {
+		p.frames = p.frames[1:]
+	}
+	return n, nil
+}
+
+func TestPlayLateStart(t *testing.T) {
+	src := &paced{delay: 300 * time.Millisecond, period: 20 * time.Millisecond}
+	for i := 0; i < 10; i++ {
+		src.frames = append(src.frames, pgm(i%8))
+	}
+	s := &screen{Image: mono.New(image.Rect(0, 0, 8, 8))}
+
+	stats, err := Play(s, NewReader(src, PNM, image.Rect(0, 0, 8, 8), nil), 50)
+	if err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	// A late source is not a slow display, so nothing is dropped
+	if stats.Shown != 10 || stats.Dropped != 0 {
+		t.Errorf("Expected 10 frames shown, got %+v", stats)
+	}
+}
+
+func TestPlayTruncated(t *testing.T) {
+	data := pgm(0)
+	s := &screen{Image: mono.New(image.Rect(0, 0, 8, 8))}
+	_, err := Play(s, NewReader(bytes.NewReader(data[:30]), PNM, image.Rect(0, 0, 8, 8), nil), 0)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, f := range []Format{PNM, Raw} {
+		if got, err := ParseFormat(f.String()); err != nil || got != f {
+			t.Errorf("Expected %s to round trip, got %s (%v)", f, got, err)
+		}
+	}
+	if _, err := ParseFormat("mp4"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,62 @@
+package text
+
+import (
+	"image"
+	"image/draw"
+	"sync"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/zachomedia/go-bdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+var embeddedFace = sync.OnceValues(func() (font.Face, error) {
+	f, err := bdf.Parse(embeddedFontData)
+	if err != nil {
+		return nil, err
+	}
+	return f.NewFace(), nil
+})
+
+// EmbeddedFace returns the embedded font, e.g. to label widgets
+func EmbeddedFace() (font.Face, error) {
+	return embeddedFace()
+}
+
+// Measure returns the width of s in pixels, summing the glyph advances
+func Measure(face font.Face, s string) int {
+	return font.MeasureString(face, s).Round()
+}
+
+// Draw writes s with the left end of its baseline at p, changing the glyph
+// pixels of dst with ink. It returns where the text ends on the baseline, so
+// more text can follow.
+func Draw(dst draw.Image, face font.Face, p image.Point, s string, ink gfx.Ink) int {
+	dot := fixed.P(p.X, p.Y)
+	prev := rune(-1)
+	for _, c := range s {
+		if prev >= 0 {
+			dot.X += face.Kern(prev, c)
+		}
+		prev = c
+
+		dr, mask, mp, advance, ok := face.Glyph(dot, c)
+		if !ok {
+			continue
+		}
+		for y := dr.Min.Y; y < dr.Max.Y; y++ {
+			for x := dr.Min.X; x < dr.Max.X; x++ {
+				m := mp.Add(image.Pt(x-dr.Min.X, y-dr.Min.Y))
+				if !m.In(mask.Bounds()) {
+					continue
+				}
+				if _, _, _, a := mask.At(m.X, m.Y).RGBA(); a >= 0x8000 {
+					gfx.Pixel(dst, x, y, ink)
+				}
+			}
+		}
+		dot.X += advance
+	}
+	return dot.X.Round()
+}
@@ -0,0 +1,59 @@
+package text
+
+import (
+	"image"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func TestDraw(t *testing.T) {
+	face, err := EmbeddedFace()
+	if err != nil {
+		t.Fatalf("EmbeddedFace failed: %v", err)
+	}
+
+	img := mono.New(image.Rect(0, 0, 32, 8))
+	end := Draw(img, face, image.Pt(2, 6), "Hi", gfx.On)
+	if want := 2 + Measure(face, "Hi"); end != want {
+		t.Errorf("Expected text to end at %d, got %d", want, end)
+	}
+
+	// Glyphs stay between the left edge and the end, above the baseline
+	lit := 0
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 32; x++ {
+			if !img.GetPixel(x, y) {
+				continue
+			}
+			lit++
+			if x < 2 || x >= end || y > 6 {
+				t.Errorf("Unexpected glyph pixel at (%d, %d)", x, y)
+			}
+		}
+	}
+	if lit == 0 {
+		t.Fatal("Expected glyph pixels to be lit")
+	}
+
+	// Drawing again with XOR erases the text
+	Draw(img, face, image.Pt(2, 6), "Hi", gfx.XOR)
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 32; x++ {
+			if img.GetPixel(x, y) {
+				t.Fatalf("Expected XOR to erase the text, (%d, %d) is lit", x, y)
+			}
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	face, _ := EmbeddedFace()
+	if Measure(face, "") != 0 {
+		t.Error("Expected an empty string to measure 0")
+	}
+	if a, ab := Measure(face, "a"), Measure(face, "ab"); ab <= a {
+		t.Errorf("Expected longer text to be wider, got %d and %d", a, ab)
+	}
+}
@@ -6,12 +6,29 @@ import (
 	"image/color"
 	"image/draw"
 	"os"
+	"unicode/utf8"
 
+	"github.com/danielgatis/go-sh1106/pkg/mono"
 	"github.com/zachomedia/go-bdf"
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
 )
 
+// Align tells where a line is placed horizontally
+type Align int
+
+const (
+	// AlignDefault follows the default alignment of the renderer, which is
+	// left unless configured otherwise
+	AlignDefault Align = iota
+	// AlignLeft starts lines at the left padding
+	AlignLeft
+	// AlignCenter centers lines between the paddings
+	AlignCenter
+	// AlignRight ends lines at the right padding
+	AlignRight
+)
+
 // Renderer handles text rendering on an image canvas
 type Renderer struct {
 	img        *image.RGBA
@@ -21,6 +38,12 @@ type Renderer struct {
 	lineCount  int
 	lineHeight int
 	lines      []string
+	aligns     []Align
+	align      Align
+	padding    int
+	fg         color.Color
+	bg         color.Color
+	glyphs     map[rune]*mono.Image
 }
 
 // Config holds configuration for the text renderer
@@ -28,6 +51,16 @@ type Config struct {
 	Width     int
 	Height    int
 	LineCount int
+
+	// Align is the default alignment of lines, AlignDefault meaning left
+	Align Align
+	// Padding is the horizontal space in pixels between the edges and the
+	// aligned text
+	Padding int
+
+	// Polarity must match the display polarity so that glyphs are drawn
+	// with lit pixels on a dark background. The zero value is mono.LitIsWhite.
+	Polarity mono.Polarity
 }
 
 // ConfigError represents a configuration validation error
@@ -68,6 +101,12 @@ func newRendererFromBytes(fontBytes []byte, config *Config) (*Renderer, error) {
 	if config.LineCount < 0 {
 		return nil, &ConfigError{Field: "LineCount", Value: config.LineCount, Message: "line count must be non-negative"}
 	}
+	if config.Align < AlignDefault || config.Align > AlignRight {
+		return nil, &ConfigError{Field: "Align", Value: config.Align, Message: "unknown alignment"}
+	}
+	if config.Padding < 0 || 2*config.Padding >= config.Width {
+		return nil, &ConfigError{Field: "Padding", Value: config.Padding, Message: "padding must be non-negative and leave room for text"}
+	}
 
 	bdfFont, err := bdf.Parse(fontBytes)
 	if err != nil {
@@ -75,10 +114,12 @@ func newRendererFromBytes(fontBytes []byte, config *Config) (*Renderer, error) {
 	}
 
 	face := bdfFont.NewFace()
+	fg := config.Polarity.Color(true)
+	bg := config.Polarity.Color(false)
 
 	rect := image.Rect(0, 0, config.Width, config.Height)
 	img := image.NewRGBA(rect)
-	draw.Draw(img, img.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+	draw.Draw(img, img.Bounds(), &image.Uniform{bg}, image.Point{}, draw.Src)
 
 	lineHeight := 1
 	if config.LineCount > 0 {
@@ -93,6 +134,12 @@ func newRendererFromBytes(fontBytes []byte, config *Config) (*Renderer, error) {
 		lineCount:  config.LineCount,
 		lineHeight: lineHeight,
 		lines:      make([]string, config.LineCount),
+		aligns:     make([]Align, config.LineCount),
+		align:      config.Align,
+		padding:    config.Padding,
+		fg:         fg,
+		bg:         bg,
+		glyphs:     make(map[rune]*mono.Image),
 	}, nil
 }
 
@@ -123,14 +170,69 @@ func (r *Renderer) Clear() {
 	r.redraw()
 }
 
+// SetAlign sets the default alignment, used by lines aligned with
+// AlignDefault
+func (r *Renderer) SetAlign(a Align) {
+	r.align = a
+	r.redraw()
+}
+
+// SetLineAlign sets the alignment of a specific line, overriding the default
+// unless a is AlignDefault. Alignments are kept when the text changes.
+func (r *Renderer) SetLineAlign(line int, a Align) {
+	if line < 0 || line >= r.lineCount {
+		return
+	}
+	r.aligns[line] = a
+	r.redraw()
+}
+
+// lineX returns where a line starts. Lines wider than the space between the
+// paddings start at the left padding, so their beginning stays visible.
+func (r *Renderer) lineX(row int, line string) int {
+	a := r.aligns[row]
+	if a == AlignDefault {
+		a = r.align
+	}
+
+	x := r.padding
+	free := r.width - 2*r.padding - r.measure(line)
+	switch {
+	case free <= 0:
+	case a == AlignCenter:
+		x += free / 2
+	case a == AlignRight:
+		x += free
+	}
+	return x
+}
+
+// measure returns the width of a line in pixels, from the glyph advances of
+// the face and the custom glyphs, as drawLine lays it out
+func (r *Renderer) measure(line string) int {
+	w := fixed.Int26_6(0)
+	start := 0
+	for i, c := range line {
+		g, ok := r.glyphs[c]
+		if !ok {
+			continue
+		}
+		w += font.MeasureString(r.face, line[start:i])
+		w += fixed.I(g.Rect.Dx() + 1)
+		start = i + utf8.RuneLen(c)
+	}
+	w += font.MeasureString(r.face, line[start:])
+	return w.Round()
+}
+
 // redraw redraws the image with all lines
 func (r *Renderer) redraw() {
 	// Clear the canvas
-	draw.Draw(r.img, r.img.Bounds(), &image.Uniform{color.Black}, image.Point{}, draw.Src)
+	draw.Draw(r.img, r.img.Bounds(), &image.Uniform{r.bg}, image.Point{}, draw.Src)
 
 	d := &font.Drawer{
 		Dst:  r.img,
-		Src:  image.NewUniform(color.White),
+		Src:  image.NewUniform(r.fg),
 		Face: r.face,
 	}
 
@@ -140,13 +242,54 @@ func (r *Renderer) redraw() {
 		}
 
 		d.Dot = fixed.Point26_6{
-			X: fixed.I(0),
+			X: fixed.I(r.lineX(row, line)),
 			Y: fixed.I((row+1)*r.lineHeight - 1), // Adjust baseline
 		}
-		d.DrawString(line)
+		r.drawLine(d, line)
 	}
 }
 
+// drawLine draws a line of text, replacing runes that have a custom glyph
+func (r *Renderer) drawLine(d *font.Drawer, line string) {
+	start := 0
+	for i, c := range line {
+		g, ok := r.glyphs[c]
+		if !ok {
+			continue
+		}
+		d.DrawString(line[start:i])
+		r.drawGlyph(d, g)
+		start = i + utf8.RuneLen(c)
+	}
+	d.DrawString(line[start:])
+}
+
+// drawGlyph draws a custom glyph standing on the baseline and advances the dot
+func (r *Renderer) drawGlyph(d *font.Drawer, g *mono.Image) {
+	x0 := d.Dot.X.Round()
+	y0 := d.Dot.Y.Round() - g.Rect.Dy()
+	for y := 0; y < g.Rect.Dy(); y++ {
+		for x := 0; x < g.Rect.Dx(); x++ {
+			if g.GetPixel(g.Rect.Min.X+x, g.Rect.Min.Y+y) {
+				r.img.Set(x0+x, y0+y, r.fg)
+			}
+		}
+	}
+	d.Dot.X += fixed.I(g.Rect.Dx() + 1)
+}
+
+// SetGlyph draws the lit pixels of g in place of rune c, e.g. to show icons
+// inline with text. The glyph stands on the baseline and is followed by one
+// pixel of spacing. A nil glyph restores the font glyph.
+func (r *Renderer) SetGlyph(c rune, g *mono.Image) {
+	if g == nil {
+		delete(r.glyphs, c)
+	} else {
+		r.glyphs[c] = g
+	}
+	r.redraw()
+}
+
 // Image returns the rendered image
 func (r *Renderer) Image() image.Image {
 	return r.img
@@ -3,6 +3,8 @@ package text
 import (
 	"image"
 	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
 )
 
 func TestTextRendererConfig(t *testing.T) {
@@ -133,3 +135,171 @@ func TestEmbeddedFontData(t *testing.T) {
 		t.Errorf("Expected BDF file to start with 'STARTFONT ', got '%s'", header)
 	}
 }
+
+func TestRendererPolarity(t *testing.T) {
+	for _, polarity := range []mono.Polarity{mono.LitIsWhite, mono.LitIsBlack} {
+		renderer, err := NewRendererWithEmbeddedFont(&Config{
+			Width:     128,
+			Height:    64,
+			LineCount: 6,
+			Polarity:  polarity,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create renderer: %v", err)
+		}
+
+		renderer.SetText("#", 0)
+
+		// The background must map to dark pixels and some glyph pixel to a lit one
+		img := renderer.Image()
+		if polarity.Lit(img.At(127, 63)) {
+			t.Errorf("%s: expected background to be dark", polarity)
+		}
+
+		lit := false
+		for y := 0; y < 10 && !lit; y++ {
+			for x := 0; x < 8 && !lit; x++ {
+				lit = polarity.Lit(img.At(x, y))
+			}
+		}
+		if !lit {
+			t.Errorf("%s: expected glyph pixels to be lit", polarity)
+		}
+	}
+}
+
+func TestRendererSetGlyph(t *testing.T) {
+	renderer, err := NewRendererWithEmbeddedFont(&Config{Width: 64, Height: 16, LineCount: 1})
+	if err != nil {
+		t.Fatalf("Failed to create renderer: %v", err)
+	}
+
+	// A 4x4 solid block in place of the private use rune U+E000
+	block := mono.New(image.Rect(0, 0, 4, 4))
+	block.Fill(true)
+	renderer.SetGlyph('\uE000', block)
+	renderer.SetText("\uE000", 0)
+
+	// The baseline is on the last row, so the block covers rows 11 to 14
+	img := renderer.Image()
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 8; x++ {
+			want := x < 4 && y >= 11 && y < 15
+			if got := mono.LitIsWhite.Lit(img.At(x, y)); got != want {
+				t.Errorf("Expected pixel (%d, %d) lit to be %v, got %v", x, y, want, got)
+			}
+		}
+	}
+
+	// Text after the glyph starts past its advance
+	renderer.SetText("\uE000|", 0)
+	lit := false
+	for y := 0; y < 16; y++ {
+		lit = lit || mono.LitIsWhite.Lit(img.At(4, y))
+	}
+	if lit {
+		t.Error("Expected a blank column after the glyph")
+	}
+
+	renderer.SetGlyph('\uE000', nil)
+	if mono.LitIsWhite.Lit(img.At(0, 14)) && mono.LitIsWhite.Lit(img.At(3, 11)) {
+		t.Error("Expected the glyph to be removed")
+	}
+}
+
+// litColumns returns the first and last columns with lit pixels in rows
+// y0 to y1 of the renderer image, or -1, -1 when there are none
+func litColumns(r *Renderer, y0, y1 int) (int, int) {
+	first, last := -1, -1
+	img := r.Image()
+	for x := 0; x < r.width; x++ {
+		for y := y0; y < y1; y++ {
+			if mono.LitIsWhite.Lit(img.At(x, y)) {
+				if first < 0 {
+					first = x
+				}
+				last = x
+				break
+			}
+		}
+	}
+	return first, last
+}
+
+func TestRendererAlign(t *testing.T) {
+	renderer, err := NewRendererWithEmbeddedFont(&Config{Width: 128, Height: 32, LineCount: 2, Padding: 4})
+	if err != nil {
+		t.Fatalf("Failed to create renderer: %v", err)
+	}
+
+	// A bar is a single column wide, so where it lands tells the start of
+	// the line
+	header := "=== MENU ==="
+	width := Measure(renderer.face, header)
+	renderer.SetTexts([]string{header, "|"})
+
+	if got, want := renderer.lineX(0, header), 4; got != want {
+		t.Errorf("Expected left aligned text at the padding %d, got %d", want, got)
+	}
+
+	renderer.SetLineAlign(0, AlignCenter)
+	if got, want := renderer.lineX(0, header), 4+(120-width)/2; got != want {
+		t.Errorf("Expected centered text at %d, got %d", want, got)
+	}
+	first, last := litColumns(renderer, 0, 16)
+	if left, right := first, 127-last; left-right > 2 || right-left > 2 {
+		t.Errorf("Expected centered text to have even margins, got %d and %d", left, right)
+	}
+
+	// The second line follows the default alignment
+	renderer.SetAlign(AlignRight)
+	if got, want := renderer.lineX(1, "|"), 124-Measure(renderer.face, "|"); got != want {
+		t.Errorf("Expected right aligned text at %d, got %d", want, got)
+	}
+	if _, last := litColumns(renderer, 16, 32); last >= 124 || last < 118 {
+		t.Errorf("Expected right aligned text to end before the padding, got column %d", last)
+	}
+	if got := renderer.lineX(0, header); got != 4+(120-width)/2 {
+		t.Errorf("Expected the line alignment to override the default, got %d", got)
+	}
+
+	// Alignments are kept when the text changes
+	renderer.SetLineAlign(0, AlignDefault)
+	renderer.SetText("|", 0)
+	if a, b := renderer.lineX(0, "|"), renderer.lineX(1, "|"); a != b {
+		t.Errorf("Expected both lines to be right aligned, got %d and %d", a, b)
+	}
+}
+
+func TestRendererAlignOverflow(t *testing.T) {
+	renderer, err := NewRendererWithEmbeddedFont(&Config{Width: 20, Height: 8, LineCount: 1, Align: AlignRight, Padding: 1})
+	if err != nil {
+		t.Fatalf("Failed to create renderer: %v", err)
+	}
+	if got := renderer.lineX(0, "much too long"); got != 1 {
+		t.Errorf("Expected overflowing text at the padding, got %d", got)
+	}
+}
+
+func TestRendererAlignGlyph(t *testing.T) {
+	renderer, err := NewRendererWithEmbeddedFont(&Config{Width: 64, Height: 16, LineCount: 1})
+	if err != nil {
+		t.Fatalf("Failed to create renderer: %v", err)
+	}
+	block := mono.New(image.Rect(0, 0, 6, 4))
+	renderer.SetGlyph('\uE000', block)
+
+	// A custom glyph advances by its width and one pixel of spacing
+	if got, want := renderer.measure("a\uE000b"), Measure(renderer.face, "ab")+7; got != want {
+		t.Errorf("Expected width %d, got %d", want, got)
+	}
+}
+
+func TestRendererInvalidPadding(t *testing.T) {
+	for _, padding := range []int{-1, 64} {
+		_, err := NewRendererWithEmbeddedFont(&Config{Width: 128, Height: 64, LineCount: 6, Padding: padding})
+		if err == nil {
+			t.Errorf("Expected error for padding %d, got nil", padding)
+		}
+	}
+}
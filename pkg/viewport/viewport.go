@@ -0,0 +1,186 @@
+// Package viewport maps drawing onto a clipped, offset and scaled area of a
+// monochrome canvas, e.g. to lay out panels and widgets.
+package viewport
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/danielgatis/go-sh1106/internal/raster"
+)
+
+// Target is the canvas a viewport draws onto, such as *display.SH1106,
+// *mono.Image, *layer.Layer or another *Viewport
+type Target interface {
+	draw.Image
+	GetPixel(x, y int) bool
+	SetPixel(x, y int, on bool)
+}
+
+// Updater is implemented by targets that show their content on Update, such
+// as *display.SH1106
+type Updater interface {
+	Update() error
+}
+
+var _ Target = (*Viewport)(nil)
+
+// Viewport is an area of a target with its own coordinate system. Local
+// pixels are scaled up to blocks of target pixels and everything outside the
+// area is clipped, so drawing code never has to offset or bounds check.
+// It implements draw.Image, so the gfx primitives, sprites and image/draw
+// can paint on it, and nested viewports can be made for widget trees.
+type Viewport struct {
+	target Target
+	area   image.Rectangle
+	clip   image.Rectangle
+	origin image.Point
+	scale  int
+}
+
+// New returns a viewport showing r, in target coordinates, clipped to the
+// target bounds, so nested viewports never draw outside their parent. Each
+// local pixel covers scale by scale target pixels; a scale below 1 means 1.
+// The local origin (0, 0) is at the top-left of r.
+func New(target Target, r image.Rectangle, scale int) *Viewport {
+	return &Viewport{
+		target: target,
+		area:   r.Canon(),
+		clip:   r.Intersect(target.Bounds()),
+		scale:  max(scale, 1),
+	}
+}
+
+// Sub returns a nested viewport showing r in the local coordinates of v
+func (v *Viewport) Sub(r image.Rectangle, scale int) *Viewport {
+	return New(v, r, scale)
+}
+
+// Target returns the canvas v draws onto
+func (v *Viewport) Target() Target {
+	return v.target
+}
+
+// Rect returns the area of the target covered by v, clipped to the target
+func (v *Viewport) Rect() image.Rectangle {
+	return v.clip
+}
+
+// Scale returns the number of target pixels per local pixel along each axis
+func (v *Viewport) Scale() int {
+	return v.scale
+}
+
+// SetOrigin sets the local point shown at the top-left of the area, e.g. to
+// scroll the content
+func (v *Viewport) SetOrigin(p image.Point) {
+	v.origin = p
+}
+
+// Origin returns the local point shown at the top-left of the area
+func (v *Viewport) Origin() image.Point {
+	return v.origin
+}
+
+// Bounds returns the visible area in local coordinates
+func (v *Viewport) Bounds() image.Rectangle {
+	if v.clip.Empty() {
+		return image.Rectangle{}
+	}
+	size := v.area.Size().Div(v.scale)
+	full := image.Rectangle{Min: v.origin, Max: v.origin.Add(size)}
+	// Local pixels at least partly inside the clip
+	visible := image.Rectangle{
+		Min: v.FromTarget(v.clip.Min),
+		Max: v.FromTarget(v.clip.Max.Sub(image.Pt(1, 1))).Add(image.Pt(1, 1)),
+	}
+	return full.Intersect(visible)
+}
+
+// ToTarget returns the target point at the top-left of the local pixel p
+func (v *Viewport) ToTarget(p image.Point) image.Point {
+	return p.Sub(v.origin).Mul(v.scale).Add(v.area.Min)
+}
+
+// FromTarget returns the local pixel covering the target point p
+func (v *Viewport) FromTarget(p image.Point) image.Point {
+	d := p.Sub(v.area.Min)
+	return image.Pt(raster.FloorDiv(d.X, v.scale), raster.FloorDiv(d.Y, v.scale)).Add(v.origin)
+}
+
+// block returns the target pixels covered by the local pixel at x, y, or an
+// empty rectangle when it is clipped
+func (v *Viewport) block(x, y int) image.Rectangle {
+	if !image.Pt(x, y).In(v.Bounds()) {
+		return image.Rectangle{}
+	}
+	p := v.ToTarget(image.Pt(x, y))
+	return image.Rectangle{Min: p, Max: p.Add(image.Pt(v.scale, v.scale))}.Intersect(v.clip)
+}
+
+// ColorModel returns the color model of the target
+func (v *Viewport) ColorModel() color.Model {
+	return v.target.ColorModel()
+}
+
+// At returns the color of a local pixel, transparent outside the bounds
+func (v *Viewport) At(x, y int) color.Color {
+	b := v.block(x, y)
+	if b.Empty() {
+		return color.Transparent
+	}
+	return v.target.At(b.Min.X, b.Min.Y)
+}
+
+// Set sets a local pixel to c, mapped to lit or dark by the target polarity
+func (v *Viewport) Set(x, y int, c color.Color) {
+	b := v.block(x, y)
+	for ty := b.Min.Y; ty < b.Max.Y; ty++ {
+		for tx := b.Min.X; tx < b.Max.X; tx++ {
+			v.target.Set(tx, ty, c)
+		}
+	}
+}
+
+// GetPixel reports whether a local pixel is lit
+func (v *Viewport) GetPixel(x, y int) bool {
+	b := v.block(x, y)
+	return !b.Empty() && v.target.GetPixel(b.Min.X, b.Min.Y)
+}
+
+// SetPixel lights or darkens a local pixel
+func (v *Viewport) SetPixel(x, y int, on bool) {
+	b := v.block(x, y)
+	for ty := b.Min.Y; ty < b.Max.Y; ty++ {
+		for tx := b.Min.X; tx < b.Max.X; tx++ {
+			v.target.SetPixel(tx, ty, on)
+		}
+	}
+}
+
+// Fill lights or darkens the whole area
+func (v *Viewport) Fill(on bool) {
+	for y := v.clip.Min.Y; y < v.clip.Max.Y; y++ {
+		for x := v.clip.Min.X; x < v.clip.Max.X; x++ {
+			v.target.SetPixel(x, y, on)
+		}
+	}
+}
+
+// Draw copies src onto r in local coordinates, sp being the source point
+// aligned with r.Min, and shows the result like SH1106.Draw does. It is
+// e.g. how a text.Renderer image is put into a panel.
+func (v *Viewport) Draw(r image.Rectangle, src image.Image, sp image.Point) error {
+	draw.Draw(v, r, src, sp, draw.Src)
+	return v.Update()
+}
+
+// Update shows the content if the target, or the root of nested viewports,
+// implements Updater, e.g. a display. Other targets are left as they are.
+func (v *Viewport) Update() error {
+	if u, ok := v.target.(Updater); ok {
+		return u.Update()
+	}
+	return nil
+}
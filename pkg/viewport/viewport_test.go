@@ -0,0 +1,149 @@
+package viewport
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/gfx"
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+// updater counts Update calls on a mono canvas
+type updater struct {
+	*mono.Image
+	updates int
+}
+
+func (u *updater) Update() error {
+	u.updates++
+	return nil
+}
+
+func TestViewport(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	v := New(img, image.Rect(10, 20, 50, 40), 1)
+
+	if want := image.Rect(0, 0, 40, 20); v.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, v.Bounds())
+	}
+	v.SetPixel(0, 0, true)
+	if !img.GetPixel(10, 20) || !v.GetPixel(0, 0) {
+		t.Error("Expected local (0, 0) to map to (10, 20)")
+	}
+
+	// Primitives are clipped to the area
+	gfx.Rect(v, image.Rect(-5, -5, 100, 100), gfx.Fill(gfx.On))
+	for _, p := range []image.Point{{9, 20}, {10, 19}, {50, 30}, {30, 40}} {
+		if img.GetPixel(p.X, p.Y) {
+			t.Errorf("Expected %v outside the area to stay dark", p)
+		}
+	}
+	if !img.GetPixel(49, 39) {
+		t.Error("Expected the bottom-right pixel of the area to be lit")
+	}
+
+	// The origin scrolls the content
+	v.Fill(false)
+	v.SetOrigin(image.Pt(100, 0))
+	if want := image.Rect(100, 0, 140, 20); v.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, v.Bounds())
+	}
+	v.SetPixel(101, 2, true)
+	v.SetPixel(0, 0, true)
+	if !img.GetPixel(11, 22) || img.GetPixel(10, 20) {
+		t.Error("Expected pixels to move with the origin")
+	}
+}
+
+func TestViewportScale(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	v := New(img, image.Rect(8, 8, 17, 17), 2)
+
+	// 9 pixels hold 4 whole local pixels
+	if want := image.Rect(0, 0, 4, 4); v.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, v.Bounds())
+	}
+	v.SetPixel(1, 1, true)
+	for y := 8; y < 17; y++ {
+		for x := 8; x < 17; x++ {
+			want := x >= 10 && x < 12 && y >= 10 && y < 12
+			if img.GetPixel(x, y) != want {
+				t.Errorf("Pixel (%d, %d): Expected %v", x, y, want)
+			}
+		}
+	}
+
+	if p := v.ToTarget(image.Pt(3, 1)); p != image.Pt(14, 10) {
+		t.Errorf("Expected (14, 10), got %v", p)
+	}
+	if p := v.FromTarget(image.Pt(15, 11)); p != image.Pt(3, 1) {
+		t.Errorf("Expected (3, 1), got %v", p)
+	}
+	if p := v.FromTarget(image.Pt(7, 7)); p != image.Pt(-1, -1) {
+		t.Errorf("Expected (-1, -1), got %v", p)
+	}
+}
+
+func TestViewportNested(t *testing.T) {
+	img := mono.New(image.Rect(0, 0, 128, 64))
+	panel := New(img, image.Rect(64, 0, 128, 32), 1)
+	// The child sticks out of its parent and is clipped
+	child := panel.Sub(image.Rect(48, 16, 80, 48), 2)
+
+	if want := image.Rect(0, 0, 8, 8); child.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, child.Bounds())
+	}
+	child.Fill(true)
+	lit := 0
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 128; x++ {
+			if img.GetPixel(x, y) {
+				lit++
+				if x < 112 || y < 16 || y >= 32 {
+					t.Fatalf("Expected (%d, %d) to be clipped", x, y)
+				}
+			}
+		}
+	}
+	if lit != 16*16 {
+		t.Errorf("Expected 256 lit pixels, got %d", lit)
+	}
+
+	// A child starting left of the target keeps its origin at r.Min
+	edge := New(img, image.Rect(-4, 40, 12, 48), 1)
+	if want := image.Rect(4, 0, 16, 8); edge.Bounds() != want {
+		t.Errorf("Expected bounds %v, got %v", want, edge.Bounds())
+	}
+	edge.SetPixel(4, 0, true)
+	if !img.GetPixel(0, 40) {
+		t.Error("Expected local (4, 0) to map to (0, 40)")
+	}
+}
+
+func TestViewportDraw(t *testing.T) {
+	u := &updater{Image: mono.New(image.Rect(0, 0, 128, 64))}
+	u.Polarity = mono.LitIsBlack
+	v := New(u, image.Rect(32, 16, 64, 32), 1)
+
+	src := image.NewGray(image.Rect(0, 0, 4, 4))
+	for i := range src.Pix {
+		src.Pix[i] = 0xFF
+	}
+	src.SetGray(1, 2, color.Gray{})
+	if err := v.Draw(image.Rect(0, 0, 4, 4), src, image.Point{}); err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+	if u.updates != 1 {
+		t.Errorf("Expected 1 update, got %d", u.updates)
+	}
+	// Black is lit under LitIsBlack, as with SH1106.Draw
+	if !u.GetPixel(33, 18) || u.GetPixel(32, 16) {
+		t.Error("Expected only the black source pixel to be lit")
+	}
+
+	// Nested viewports forward Update to the root
+	if err := v.Sub(image.Rect(0, 0, 8, 8), 1).Update(); err != nil || u.updates != 2 {
+		t.Errorf("Expected the update to reach the target, got %d (%v)", u.updates, err)
+	}
+}
@@ -0,0 +1,139 @@
+// Package xbm decodes X BitMap images, the C source format produced by
+// GIMP, ImageMagick and the X11 bitmap tool.
+//
+// Images decode to *mono.Image with the mono.LitIsBlack polarity, so the set
+// (foreground) bits are the lit pixels. Both X11 char arrays and X10 short
+// arrays are supported. Importing the package registers the format with the
+// image package.
+package xbm
+
+import (
+	"errors"
+	"image"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+func init() {
+	image.RegisterFormat("xbm", "#define", Decode, DecodeConfig)
+}
+
+// file is a parsed XBM source
+type file struct {
+	width  int
+	height int
+	short  bool
+	values []string
+}
+
+// parse reads the defines and the raw array values
+func parse(r io.Reader, withData bool) (*file, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s := string(src)
+
+	f := &file{}
+	brace := strings.IndexByte(s, '{')
+	header := s
+	if brace >= 0 {
+		header = s[:brace]
+	}
+
+	for _, line := range strings.Split(header, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == "#define" {
+			n, err := strconv.Atoi(fields[2])
+			if err != nil {
+				continue
+			}
+			switch {
+			case strings.HasSuffix(fields[1], "_width"):
+				f.width = n
+			case strings.HasSuffix(fields[1], "_height"):
+				f.height = n
+			}
+		}
+		if strings.Contains(line, "short") && strings.Contains(line, "[") {
+			f.short = true
+		}
+	}
+
+	if f.width <= 0 || f.height <= 0 {
+		return nil, errors.New("xbm: missing or invalid width and height")
+	}
+	if !withData {
+		return f, nil
+	}
+
+	if brace < 0 {
+		return nil, errors.New("xbm: missing bitmap data")
+	}
+	end := strings.IndexByte(s[brace:], '}')
+	if end < 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	for _, v := range strings.Split(s[brace+1:brace+end], ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			f.values = append(f.values, v)
+		}
+	}
+
+	return f, nil
+}
+
+// DecodeConfig returns the color model and size of an XBM image
+func DecodeConfig(r io.Reader) (image.Config, error) {
+	f, err := parse(r, false)
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: mono.Model, Width: f.width, Height: f.height}, nil
+}
+
+// Decode reads an XBM image
+func Decode(r io.Reader) (image.Image, error) {
+	return DecodeMono(r)
+}
+
+// DecodeMono reads an XBM image into a mono image
+func DecodeMono(r io.Reader) (*mono.Image, error) {
+	f, err := parse(r, true)
+	if err != nil {
+		return nil, err
+	}
+
+	bits := 8
+	if f.short {
+		bits = 16
+	}
+
+	// Rows are padded to whole values, least significant bit first
+	perRow := (f.width + bits - 1) / bits
+	if len(f.values) < perRow*f.height {
+		return nil, errors.New("xbm: not enough bitmap data")
+	}
+
+	img := mono.New(image.Rect(0, 0, f.width, f.height))
+	img.Polarity = mono.LitIsBlack
+
+	for y := 0; y < f.height; y++ {
+		for i := 0; i < perRow; i++ {
+			v, err := strconv.ParseUint(f.values[y*perRow+i], 0, bits)
+			if err != nil {
+				return nil, errors.New("xbm: invalid value " + strconv.Quote(f.values[y*perRow+i]))
+			}
+			for b := 0; b < bits; b++ {
+				if x := i*bits + b; x < f.width && v&(1<<b) != 0 {
+					img.SetPixel(x, y, true)
+				}
+			}
+		}
+	}
+
+	return img, nil
+}
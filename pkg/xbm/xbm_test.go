@@ -0,0 +1,87 @@
+package xbm
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/danielgatis/go-sh1106/pkg/mono"
+)
+
+const arrow = `#define arrow_width 10
+#define arrow_height 3
+#define arrow_x_hot 0
+static unsigned char arrow_bits[] = {
+   0x01, 0x02, 0xff, 0x03,
+   0x80, 0x00 };
+`
+
+func TestDecode(t *testing.T) {
+	img, err := DecodeMono(strings.NewReader(arrow))
+	if err != nil {
+		t.Fatalf("DecodeMono failed: %v", err)
+	}
+
+	if img.Rect != image.Rect(0, 0, 10, 3) {
+		t.Fatalf("Expected 10x3 bounds, got %v", img.Rect)
+	}
+	if img.Polarity != mono.LitIsBlack {
+		t.Errorf("Expected polarity %v, got %v", mono.LitIsBlack, img.Polarity)
+	}
+
+	want := []string{
+		"1000000001",
+		"1111111111",
+		"0000000100",
+	}
+	for y, row := range want {
+		for x, c := range row {
+			if got := img.GetPixel(x, y); got != (c == '1') {
+				t.Errorf("Expected pixel (%d, %d) to be %v, got %v", x, y, c == '1', got)
+			}
+		}
+	}
+}
+
+func TestDecodeShort(t *testing.T) {
+	src := `#define s_width 16
+#define s_height 1
+static short s_bits[] = { 0x8001 };`
+
+	img, err := DecodeMono(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("DecodeMono failed: %v", err)
+	}
+	for x := 0; x < 16; x++ {
+		want := x == 0 || x == 15
+		if got := img.GetPixel(x, 0); got != want {
+			t.Errorf("Expected pixel %d to be %v, got %v", x, want, got)
+		}
+	}
+}
+
+func TestRegisteredFormat(t *testing.T) {
+	cfg, format, err := image.DecodeConfig(strings.NewReader(arrow))
+	if err != nil {
+		t.Fatalf("DecodeConfig failed: %v", err)
+	}
+	if format != "xbm" {
+		t.Errorf("Expected format xbm, got %q", format)
+	}
+	if cfg.Width != 10 || cfg.Height != 3 {
+		t.Errorf("Expected 10x3, got %dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, src := range []string{
+		"static char x_bits[] = { 0x00 };",
+		"#define x_width 8\n#define x_height 2\nstatic char x_bits[] = { 0x00 };",
+		"#define x_width 8\n#define x_height 1\nstatic char x_bits[] = { 0xzz };",
+		"#define x_width 8\n#define x_height 1\nstatic char x_bits[] = { 0x00",
+	} {
+		if _, err := Decode(strings.NewReader(src)); err == nil {
+			t.Errorf("Expected an error for %q", src)
+		}
+	}
+}